@@ -24,6 +24,7 @@ var (
 	peach      = rl.NewColor(250, 179, 135, 255)
 	pink       = rl.NewColor(245, 194, 231, 255)
 	mauve      = rl.NewColor(203, 166, 247, 255)
+	red        = rl.NewColor(243, 139, 168, 255)
 )
 
 type Color struct {
@@ -65,6 +66,7 @@ var predefinedColors = map[string]rl.Color{
 	"peach":      peach,
 	"pink":       pink,
 	"mauve":      mauve,
+	"red":        red,
 }
 
 // UnmarshalYAML supports either hex or predefined name
@@ -241,3 +243,7 @@ func (c *colors) Yellow() rl.Color {
 func (c *colors) Peach() rl.Color {
 	return peach
 }
+
+func (c *colors) Red() rl.Color {
+	return red
+}
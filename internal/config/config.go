@@ -6,12 +6,37 @@ import (
 	"sync"
 
 	"github.com/quar15/qq-go/internal/database"
+	"github.com/quar15/qq-go/internal/format"
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
 	Connections []database.ConnectionData `yaml:"connections"`
 	Colors      colors                    `yaml:"colors,omitempty"`
+	Display     format.DisplayOptions     `yaml:"display,omitempty"`
+	Editor      EditorOptions             `yaml:"editor,omitempty"`
+}
+
+// DefaultUndoLevels is number of editor changes kept for undo when not configured
+const DefaultUndoLevels int = 1000
+
+// DefaultShiftWidth is number of spaces added by `>` when not configured
+const DefaultShiftWidth int = 4
+
+// EditorOptions controls behavior of SQL editor (`editor:` in config)
+type EditorOptions struct {
+	UndoLevels int `yaml:"undo_levels,omitempty"` // changes kept for undo, DefaultUndoLevels when not set
+	ShiftWidth int `yaml:"shift_width,omitempty"` // spaces added by `>` and removed by `<`, DefaultShiftWidth when not set
+}
+
+func (o EditorOptions) WithDefaults() EditorOptions {
+	if o.UndoLevels <= 0 {
+		o.UndoLevels = DefaultUndoLevels
+	}
+	if o.ShiftWidth <= 0 {
+		o.ShiftWidth = DefaultShiftWidth
+	}
+	return o
 }
 
 var (
@@ -23,10 +48,14 @@ var (
 func Load() (*Config, error) {
 	const connectionsConfigPath = "./config/gqq.yaml"
 	const colorsConfigPath = "./config/colors.yaml"
+	const displayConfigPath = "./config/display.yaml"
+	const editorConfigPath = "./config/editor.yaml"
 	slog.Debug(
 		"Trying to initialize config",
 		slog.String("connectionsConfigPath", connectionsConfigPath),
 		slog.String("colorsConfigPath", colorsConfigPath),
+		slog.String("displayConfigPath", displayConfigPath),
+		slog.String("editorConfigPath", editorConfigPath),
 	)
 	once.Do(func() {
 		var data []byte
@@ -53,9 +82,42 @@ func Load() (*Config, error) {
 		colorsCfg.MergeDefaults()
 		slog.Debug("Initialized colors from config", slog.Any("colorsCfg", colorsCfg))
 
+		// Display config is optional, defaults are used without it
+		displayCfg := struct {
+			Display format.DisplayOptions `yaml:"display"`
+		}{}
+		data, err = os.ReadFile(displayConfigPath)
+		if err == nil {
+			if err = yaml.Unmarshal(data, &displayCfg); err != nil {
+				return
+			}
+		} else if !os.IsNotExist(err) {
+			return
+		}
+		err = nil
+		format.SetDisplayOptions(displayCfg.Display)
+		slog.Debug("Initialized display options from config", slog.Any("displayCfg", displayCfg.Display))
+
+		// Editor config is optional as well
+		editorCfg := struct {
+			Editor EditorOptions `yaml:"editor"`
+		}{}
+		data, err = os.ReadFile(editorConfigPath)
+		if err == nil {
+			if err = yaml.Unmarshal(data, &editorCfg); err != nil {
+				return
+			}
+		} else if !os.IsNotExist(err) {
+			return
+		}
+		err = nil
+		slog.Debug("Initialized editor options from config", slog.Any("editorCfg", editorCfg.Editor))
+
 		cfg = &Config{
 			Connections: conns,
 			Colors:      colors{&colorsCfg},
+			Display:     format.GetDisplayOptions(),
+			Editor:      editorCfg.Editor.WithDefaults(),
 		}
 	})
 
@@ -9,10 +9,15 @@ import (
 )
 
 type Common struct {
-	Mode      Mode
-	CmdBuf    string
-	MotionBuf string
-	Logs      CommandLogs
+	Mode        Mode
+	CmdPrevMode Mode // mode from which command line was opened, commands are executed in it
+	CmdBuf      string
+	CmdPrefix   rune   // ':' for commands, '/' or '?' for search
+	EditBuf     string // value of spreadsheet cell being edited
+	EditOrig    string // text of cell when editing started, unchanged value is not recorded as change
+	MotionBuf   string
+	Registers   Registers
+	Logs        CommandLogs
 }
 
 type Type int8
@@ -64,6 +69,18 @@ func (c *Cursor) TransitionMode(newMode Mode) {
 	c.Common.Mode = newMode
 }
 
+func (c *Cursor) EnterCommandMode() {
+	c.EnterCommandLine(':')
+}
+
+// EnterCommandLine opens command line with given prefix, e.g. '/' for search
+func (c *Cursor) EnterCommandLine(prefix rune) {
+	c.Common.CmdPrevMode = c.Common.Mode
+	c.Common.CmdBuf = ""
+	c.Common.CmdPrefix = prefix
+	c.TransitionMode(ModeCommand)
+}
+
 func (c *Cursor) UpdateCmdLine() {
 	c.Common.Logs.Log(c.Common.CmdBuf)
 }
@@ -124,6 +141,10 @@ func (c *Cursor) IsFocused(col int32, row int32) bool {
 	return c.Position.Col == col && c.Position.Row == row
 }
 
+func (c *Cursor) IsEditingCell(col int32, row int32) bool {
+	return c.isActive && c.Common.Mode == ModeInsert && c.IsFocused(col, row)
+}
+
 func (c *Cursor) IsSelected(col int32, row int32) bool {
 	switch c.Common.Mode {
 	case ModeVisual:
@@ -0,0 +1,144 @@
+package cursor
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.design/x/clipboard"
+)
+
+// UnnamedRegister is register used when no register was selected with `"`
+const UnnamedRegister rune = '"'
+
+// ClipboardRegister is mapped to system clipboard
+const ClipboardRegister rune = '+'
+
+type RegisterKind int8
+
+const (
+	RegisterCharwise  RegisterKind = iota // yanked in VISUAL mode
+	RegisterLinewise                      // yanked in V-LINE mode
+	RegisterBlockwise                     // yanked in V-BLOCK mode
+)
+
+// Register holds yanked text split into lines, kind decides how the text is put back
+type Register struct {
+	Lines []string
+	Kind  RegisterKind
+}
+
+func (r Register) Text() string {
+	text := strings.Join(r.Lines, "\n")
+	if r.Kind == RegisterLinewise {
+		text += "\n"
+	}
+	return text
+}
+
+// RegisterFromText splits text into register, text ending with new line is linewise
+func RegisterFromText(text string) Register {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if strings.HasSuffix(text, "\n") {
+		return Register{Lines: strings.Split(strings.TrimSuffix(text, "\n"), "\n"), Kind: RegisterLinewise}
+	}
+	return Register{Lines: strings.Split(text, "\n"), Kind: RegisterCharwise}
+}
+
+// Registers are vim like registers: unnamed, named `a`-`z` (`A`-`Z` appends) and `+` for system clipboard
+type Registers struct {
+	Selected rune // register selected with `"` for next yank or put, 0 when none
+	Awaiting bool // `"` (or Ctrl+R in insert mode) was pressed and register name is expected
+	unnamed  Register
+	named    map[rune]Register
+}
+
+// Select remembers register for next yank or put
+func (r *Registers) Select(name rune) error {
+	if !isValidRegister(name) {
+		return fmt.Errorf("Invalid register name: %q", name)
+	}
+	r.Selected = name
+	return nil
+}
+
+// TakeSelected returns selected register (unnamed when none) and clears selection
+func (r *Registers) TakeSelected() rune {
+	name := r.Selected
+	r.Selected = 0
+	if name == 0 {
+		return UnnamedRegister
+	}
+	return name
+}
+
+// Set stores yanked text in register, unnamed register always gets the last yank
+func (r *Registers) Set(name rune, reg Register) error {
+	if !isValidRegister(name) {
+		return fmt.Errorf("Invalid register name: %q", name)
+	}
+	switch {
+	case name == ClipboardRegister:
+		clipboard.Write(clipboard.FmtText, []byte(reg.Text()))
+	case name >= 'a' && name <= 'z':
+		r.setNamed(name, reg)
+	case name >= 'A' && name <= 'Z':
+		name = name - 'A' + 'a'
+		if prev, ok := r.named[name]; ok {
+			reg = appendRegister(prev, reg)
+		}
+		r.setNamed(name, reg)
+	}
+	r.unnamed = reg
+	return nil
+}
+
+// Get returns content of register, empty register is an error
+func (r *Registers) Get(name rune) (Register, error) {
+	if !isValidRegister(name) {
+		return Register{}, fmt.Errorf("Invalid register name: %q", name)
+	}
+	var reg Register
+	var ok bool
+	switch {
+	case name == UnnamedRegister:
+		reg, ok = r.unnamed, r.unnamed.Lines != nil
+	case name == ClipboardRegister:
+		if text := clipboard.Read(clipboard.FmtText); len(text) > 0 {
+			reg, ok = RegisterFromText(string(text)), true
+		}
+	default:
+		reg, ok = r.named[toLowerRegister(name)]
+	}
+	if !ok {
+		return Register{}, fmt.Errorf("Nothing in register %c", name)
+	}
+	return reg, nil
+}
+
+func (r *Registers) setNamed(name rune, reg Register) {
+	if r.named == nil {
+		r.named = make(map[rune]Register)
+	}
+	r.named[name] = reg
+}
+
+// appendRegister joins text like vim does for uppercase register, linewise text makes result linewise
+func appendRegister(prev Register, next Register) Register {
+	if prev.Kind == RegisterLinewise || next.Kind == RegisterLinewise {
+		return Register{Lines: append(append([]string{}, prev.Lines...), next.Lines...), Kind: RegisterLinewise}
+	}
+	lines := append([]string{}, prev.Lines...)
+	lines[len(lines)-1] += next.Lines[0]
+	return Register{Lines: append(lines, next.Lines[1:]...), Kind: prev.Kind}
+}
+
+func isValidRegister(name rune) bool {
+	return name == UnnamedRegister || name == ClipboardRegister || (name >= 'a' && name <= 'z') || (name >= 'A' && name <= 'Z')
+}
+
+func toLowerRegister(name rune) rune {
+	if name >= 'A' && name <= 'Z' {
+		return name - 'A' + 'a'
+	}
+	return name
+}
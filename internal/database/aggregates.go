@@ -0,0 +1,132 @@
+package database
+
+import (
+	"cmp"
+	"math/big"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/quar15/qq-go/internal/format"
+)
+
+// Aggregates summarizes selected cells, numeric aggregates are nil when no numeric value was selected
+type Aggregates struct {
+	Count    int
+	NonNull  int
+	Distinct int
+	Numeric  int
+	Sum      *big.Float
+	Min      *big.Float
+	Max      *big.Float
+}
+
+func ComputeAggregates(values []any) Aggregates {
+	agg := Aggregates{Count: len(values)}
+	distinct := make(map[string]struct{})
+	for _, val := range values {
+		if val == nil {
+			continue
+		}
+		agg.NonNull++
+		distinct[format.GetValueAsString(val)] = struct{}{}
+
+		n, ok := numericValue(val)
+		if !ok {
+			continue
+		}
+		agg.Numeric++
+		if agg.Sum == nil {
+			agg.Sum = new(big.Float)
+			agg.Min, agg.Max = n, n
+		}
+		agg.Sum.Add(agg.Sum, n)
+		if n.Cmp(agg.Min) < 0 {
+			agg.Min = n
+		}
+		if n.Cmp(agg.Max) > 0 {
+			agg.Max = n
+		}
+	}
+	agg.Distinct = len(distinct)
+	return agg
+}
+
+// Avg returns average of numeric values or nil
+func (a Aggregates) Avg() *big.Float {
+	if a.Numeric == 0 {
+		return nil
+	}
+	return new(big.Float).Quo(a.Sum, new(big.Float).SetInt64(int64(a.Numeric)))
+}
+
+type ValueCount struct {
+	Value string
+	Count int
+}
+
+// ColumnProfile describes distribution of values of single column
+type ColumnProfile struct {
+	Header    string
+	Type      string
+	Rows      int
+	Nulls     int
+	Distinct  int
+	TopValues []ValueCount
+	MinLength int
+	MaxLength int
+	Min       any
+	Max       any
+}
+
+func (p ColumnProfile) NullRatio() float64 {
+	if p.Rows == 0 {
+		return 0
+	}
+	return float64(p.Nulls) / float64(p.Rows)
+}
+
+// ProfileColumn computes profile of column values, topN limits number of most frequent values
+func ProfileColumn(header string, typeName string, values []any, topN int) ColumnProfile {
+	profile := ColumnProfile{Header: header, Type: typeName, Rows: len(values), MinLength: -1}
+	counts := make(map[string]int)
+	compare := ValueComparer(values)
+	for _, val := range values {
+		if val == nil {
+			profile.Nulls++
+			continue
+		}
+		text := format.GetValueAsString(val)
+		counts[text]++
+
+		length := utf8.RuneCountInString(text)
+		if profile.MinLength < 0 || length < profile.MinLength {
+			profile.MinLength = length
+		}
+		profile.MaxLength = max(profile.MaxLength, length)
+
+		if profile.Min == nil || compare(val, profile.Min) < 0 {
+			profile.Min = val
+		}
+		if profile.Max == nil || compare(val, profile.Max) > 0 {
+			profile.Max = val
+		}
+	}
+	profile.MinLength = max(profile.MinLength, 0)
+	profile.Distinct = len(counts)
+
+	profile.TopValues = make([]ValueCount, 0, len(counts))
+	for value, count := range counts {
+		profile.TopValues = append(profile.TopValues, ValueCount{Value: value, Count: count})
+	}
+	slices.SortFunc(profile.TopValues, func(a, b ValueCount) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Value, b.Value)
+	})
+	if len(profile.TopValues) > topN {
+		profile.TopValues = profile.TopValues[:topN]
+	}
+
+	return profile
+}
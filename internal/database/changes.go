@@ -0,0 +1,551 @@
+package database
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/quar15/qq-go/internal/export"
+	"github.com/quar15/qq-go/internal/format"
+)
+
+var statementPlaceholder = regexp.MustCompile(`\$[0-9]+`)
+
+type CellEdit struct {
+	OldValue any
+	NewValue any
+}
+
+type rowEdits struct {
+	row   map[string]any
+	cells map[string]*CellEdit
+}
+
+type changeKind int8
+
+const (
+	changeEdit changeKind = iota
+	changeInsert
+	changeDelete
+)
+
+type changeStep struct {
+	kind    changeKind
+	key     string
+	row     map[string]any
+	column  string
+	prev    any
+	hadEdit bool
+	edit    CellEdit
+}
+
+// ChangeSet tracks pending modifications of editable grid. Existing rows are addressed by primary key,
+// so changes survive reordering of grid rows. Inserted rows get synthetic keys until they are committed.
+type ChangeSet struct {
+	mu           sync.RWMutex
+	edits        map[string]*rowEdits
+	order        []string
+	inserted     map[uintptr]string
+	insertedRows map[string]map[string]any
+	insertOrder  []string
+	deleted      map[string]map[string]any
+	deleteOrder  []string
+	history      []changeStep
+	nextInsertID int
+	failedKey    string
+	failedErr    string
+	revision     int // increased by every modification, unlike Len it changes when edited cell is edited again
+}
+
+type StatementKind int8
+
+const (
+	StatementUpdate StatementKind = iota
+	StatementInsert
+	StatementDelete
+)
+
+// Statement is single parametrized SQL statement generated from pending changes
+type Statement struct {
+	Kind   StatementKind
+	SQL    string
+	Args   []any
+	RowKey string
+}
+
+func NewChangeSet() *ChangeSet {
+	cs := &ChangeSet{}
+	cs.reset()
+	return cs
+}
+
+func (cs *ChangeSet) reset() {
+	cs.edits = make(map[string]*rowEdits)
+	cs.order = nil
+	cs.inserted = make(map[uintptr]string)
+	cs.insertedRows = make(map[string]map[string]any)
+	cs.insertOrder = nil
+	cs.deleted = make(map[string]map[string]any)
+	cs.deleteOrder = nil
+	cs.history = nil
+	cs.failedKey = ""
+	cs.failedErr = ""
+}
+
+// rowIdentity identifies the row map itself (maps are references), used for rows without committed primary key
+func rowIdentity(row map[string]any) uintptr {
+	return reflect.ValueOf(row).Pointer()
+}
+
+// RowKey identifies row by values of primary key columns or by synthetic key of inserted row
+func (dg *DataGrid) RowKey(row map[string]any) string {
+	if key, ok := dg.Changes.insertedKey(row); ok {
+		return key
+	}
+	values := make([]string, len(dg.Source.PrimaryKey))
+	for i, header := range dg.Source.PrimaryKey {
+		values[i] = format.GetValueAsString(row[header])
+	}
+	return strings.Join(values, "\x1f")
+}
+
+func (cs *ChangeSet) insertedKey(row map[string]any) (string, bool) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	key, ok := cs.inserted[rowIdentity(row)]
+	return key, ok
+}
+
+// Revision identifies state of changes, views derived from grid (e.g. aggregates) are computed again when it changes
+func (cs *ChangeSet) Revision() int {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.revision
+}
+
+func (cs *ChangeSet) Len() int {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return len(cs.edits) + len(cs.insertedRows) + len(cs.deleted)
+}
+
+// EditCell sets new value of the cell and records it as pending change
+func (dg *DataGrid) EditCell(row int32, col int32, value any) error {
+	if !dg.IsEditable() {
+		return fmt.Errorf("Result is not editable (single table with primary key required)")
+	}
+	if row < 0 || row >= dg.Rows || col < 0 || col >= dg.Cols {
+		return fmt.Errorf("Cell %d:%d out of range", row+1, col+1)
+	}
+	rowData := dg.Data[row]
+	header := dg.Headers[col]
+	key := dg.RowKey(rowData)
+
+	cs := dg.Changes
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.revision++
+
+	_, isInserted := cs.insertedRows[key]
+	if dg.IsPrimaryKeyColumn(header) && !isInserted {
+		return fmt.Errorf("Primary key column '%s' cannot be edited", header)
+	}
+	if _, isDeleted := cs.deleted[key]; isDeleted {
+		return fmt.Errorf("Row %d is marked for deletion", row+1)
+	}
+
+	step := changeStep{kind: changeEdit, key: key, row: rowData, column: header, prev: rowData[header]}
+	re, ok := cs.edits[key]
+	if !ok {
+		re = &rowEdits{row: rowData, cells: map[string]*CellEdit{}}
+		cs.edits[key] = re
+		cs.order = append(cs.order, key)
+	}
+	edit, hadEdit := re.cells[header]
+	if hadEdit {
+		step.hadEdit = true
+		step.edit = *edit
+	} else {
+		edit = &CellEdit{OldValue: rowData[header]}
+		re.cells[header] = edit
+	}
+	edit.NewValue = value
+	rowData[header] = value
+
+	// Editing value back to original removes pending change (inserted rows keep explicit values)
+	if valuesEqual(edit.OldValue, edit.NewValue) && !isInserted {
+		delete(re.cells, header)
+		cs.dropRowIfEmpty(key)
+	}
+	cs.history = append(cs.history, step)
+
+	return nil
+}
+
+// InsertRow adds empty row at given index and records it as pending INSERT
+func (dg *DataGrid) InsertRow(at int32) error {
+	if !dg.IsEditable() {
+		return fmt.Errorf("Result is not editable (single table with primary key required)")
+	}
+	at = min(max(at, 0), dg.Rows)
+	rowData := make(map[string]any, len(dg.AllHeaders()))
+	for _, header := range dg.AllHeaders() {
+		rowData[header] = nil
+	}
+
+	cs := dg.Changes
+	cs.mu.Lock()
+	cs.revision++
+	cs.nextInsertID++
+	key := fmt.Sprintf("+%d", cs.nextInsertID)
+	cs.inserted[rowIdentity(rowData)] = key
+	cs.insertedRows[key] = rowData
+	cs.insertOrder = append(cs.insertOrder, key)
+	cs.history = append(cs.history, changeStep{kind: changeInsert, key: key, row: rowData})
+	cs.mu.Unlock()
+
+	var after map[string]any
+	if at > 0 {
+		after = dg.Data[at-1]
+	}
+	dg.insertIntoAllRows(rowData, after)
+	dg.Data = slices.Insert(dg.Data, int(at), rowData)
+	dg.Rows++
+	return nil
+}
+
+// ToggleRowDeleted marks row for pending DELETE (or unmarks it). Inserted rows are removed from grid immediately.
+func (dg *DataGrid) ToggleRowDeleted(row int32) error {
+	if !dg.IsEditable() {
+		return fmt.Errorf("Result is not editable (single table with primary key required)")
+	}
+	if row < 0 || row >= dg.Rows {
+		return fmt.Errorf("Row %d out of range", row+1)
+	}
+	rowData := dg.Data[row]
+	key := dg.RowKey(rowData)
+
+	cs := dg.Changes
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.revision++
+
+	if _, isInserted := cs.insertedRows[key]; isInserted {
+		cs.forgetInserted(key, rowData)
+		dg.removeFromAllRows(func(r map[string]any) bool { return rowIdentity(r) == rowIdentity(rowData) })
+		dg.Data = slices.Delete(dg.Data, int(row), int(row)+1)
+		dg.Rows--
+		return nil
+	}
+
+	step := changeStep{kind: changeDelete, key: key, row: rowData}
+	if _, isDeleted := cs.deleted[key]; isDeleted {
+		step.hadEdit = true
+		cs.unmarkDeleted(key)
+	} else {
+		cs.deleted[key] = rowData
+		cs.deleteOrder = append(cs.deleteOrder, key)
+	}
+	cs.history = append(cs.history, step)
+	return nil
+}
+
+func (dg *DataGrid) IsCellEdited(row int32, col int32) bool {
+	if !dg.IsEditable() || dg.Changes.Len() == 0 || row >= dg.Rows || col >= dg.Cols {
+		return false
+	}
+	cs := dg.Changes
+	key := dg.RowKey(dg.Data[row])
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	re, ok := cs.edits[key]
+	if !ok {
+		return false
+	}
+	_, ok = re.cells[dg.Headers[col]]
+	return ok
+}
+
+func (dg *DataGrid) IsRowInserted(row int32) bool {
+	if !dg.IsEditable() || row >= dg.Rows {
+		return false
+	}
+	_, ok := dg.Changes.insertedKey(dg.Data[row])
+	return ok
+}
+
+func (dg *DataGrid) IsRowDeleted(row int32) bool {
+	if !dg.IsEditable() || dg.Changes.Len() == 0 || row >= dg.Rows {
+		return false
+	}
+	key := dg.RowKey(dg.Data[row])
+	dg.Changes.mu.RLock()
+	defer dg.Changes.mu.RUnlock()
+	_, ok := dg.Changes.deleted[key]
+	return ok
+}
+
+// RowError returns error of last failed commit when it was caused by given row
+func (dg *DataGrid) RowError(row int32) (string, bool) {
+	if !dg.IsEditable() || row >= dg.Rows {
+		return "", false
+	}
+	cs := dg.Changes
+	cs.mu.RLock()
+	failedKey, failedErr := cs.failedKey, cs.failedErr
+	cs.mu.RUnlock()
+	if failedKey == "" || dg.RowKey(dg.Data[row]) != failedKey {
+		return "", false
+	}
+	return failedErr, true
+}
+
+// FindRowByKey returns index of row with given key or -1 when row is not in the grid
+func (dg *DataGrid) FindRowByKey(key string) int32 {
+	for i, row := range dg.Data {
+		if dg.RowKey(row) == key {
+			return int32(i)
+		}
+	}
+	return -1
+}
+
+func (cs *ChangeSet) SetFailedRow(key string, err error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.failedKey = key
+	cs.failedErr = err.Error()
+}
+
+// Undo reverts last change of the grid, returns false when there is nothing to undo
+func (dg *DataGrid) Undo() bool {
+	if !dg.IsEditable() {
+		return false
+	}
+	cs := dg.Changes
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.revision++
+	if len(cs.history) == 0 {
+		return false
+	}
+	step := cs.history[len(cs.history)-1]
+	cs.history = cs.history[:len(cs.history)-1]
+
+	switch step.kind {
+	case changeInsert:
+		if idx := slices.IndexFunc(dg.Data, func(row map[string]any) bool { return rowIdentity(row) == rowIdentity(step.row) }); idx >= 0 {
+			dg.Data = slices.Delete(dg.Data, idx, idx+1)
+			dg.Rows--
+		}
+		dg.removeFromAllRows(func(r map[string]any) bool { return rowIdentity(r) == rowIdentity(step.row) })
+		cs.forgetInserted(step.key, step.row)
+	case changeDelete:
+		if step.hadEdit {
+			cs.deleted[step.key] = step.row
+			cs.deleteOrder = append(cs.deleteOrder, step.key)
+		} else {
+			cs.unmarkDeleted(step.key)
+		}
+	case changeEdit:
+		step.row[step.column] = step.prev
+		re, ok := cs.edits[step.key]
+		if !ok {
+			re = &rowEdits{row: step.row, cells: map[string]*CellEdit{}}
+			cs.edits[step.key] = re
+			cs.order = append(cs.order, step.key)
+		}
+		if step.hadEdit {
+			edit := step.edit
+			re.cells[step.column] = &edit
+		} else {
+			delete(re.cells, step.column)
+		}
+		cs.dropRowIfEmpty(step.key)
+	}
+
+	return true
+}
+
+// Discard reverts all pending changes of the grid
+func (dg *DataGrid) Discard() {
+	for dg.Undo() {
+	}
+	dg.Changes.Clear()
+}
+
+// Clear forgets pending changes without reverting grid values (e.g. after commit)
+func (cs *ChangeSet) Clear() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.revision++
+	cs.reset()
+}
+
+// ApplyCommitted drops rows deleted by successful commit from the grid and forgets pending changes
+func (dg *DataGrid) ApplyCommitted() {
+	cs := dg.Changes
+	cs.mu.RLock()
+	deleted := make(map[uintptr]bool, len(cs.deleted))
+	for _, row := range cs.deleted {
+		deleted[rowIdentity(row)] = true
+	}
+	cs.mu.RUnlock()
+
+	isDeleted := func(row map[string]any) bool {
+		return deleted[rowIdentity(row)]
+	}
+	dg.removeFromAllRows(isDeleted)
+	dg.Data = slices.DeleteFunc(dg.Data, isDeleted)
+	dg.Rows = int32(len(dg.Data))
+	cs.Clear()
+}
+
+func (cs *ChangeSet) forgetInserted(key string, row map[string]any) {
+	delete(cs.inserted, rowIdentity(row))
+	delete(cs.insertedRows, key)
+	delete(cs.edits, key)
+	if idx := slices.Index(cs.insertOrder, key); idx >= 0 {
+		cs.insertOrder = slices.Delete(cs.insertOrder, idx, idx+1)
+	}
+	if idx := slices.Index(cs.order, key); idx >= 0 {
+		cs.order = slices.Delete(cs.order, idx, idx+1)
+	}
+	cs.history = slices.DeleteFunc(cs.history, func(step changeStep) bool { return step.key == key })
+}
+
+func (cs *ChangeSet) unmarkDeleted(key string) {
+	delete(cs.deleted, key)
+	if idx := slices.Index(cs.deleteOrder, key); idx >= 0 {
+		cs.deleteOrder = slices.Delete(cs.deleteOrder, idx, idx+1)
+	}
+}
+
+func (cs *ChangeSet) dropRowIfEmpty(key string) {
+	re, ok := cs.edits[key]
+	if !ok || len(re.cells) > 0 {
+		return
+	}
+	delete(cs.edits, key)
+	if idx := slices.Index(cs.order, key); idx >= 0 {
+		cs.order = slices.Delete(cs.order, idx, idx+1)
+	}
+}
+
+// Statements generates DELETE, UPDATE and INSERT statements for pending changes (in that order)
+func (dg *DataGrid) Statements() []Statement {
+	if !dg.IsEditable() {
+		return nil
+	}
+	cs := dg.Changes
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	table := dg.Source.Identifier().Sanitize()
+	statements := make([]Statement, 0, len(cs.deleteOrder)+len(cs.order)+len(cs.insertOrder))
+
+	for _, key := range cs.deleteOrder {
+		var args []any
+		statements = append(statements, Statement{
+			Kind:   StatementDelete,
+			SQL:    fmt.Sprintf("DELETE FROM %s WHERE %s", table, dg.primaryKeyCondition(cs.deleted[key], &args)),
+			Args:   args,
+			RowKey: key,
+		})
+	}
+
+	for _, key := range cs.order {
+		if _, isInserted := cs.insertedRows[key]; isInserted {
+			continue
+		}
+		if _, isDeleted := cs.deleted[key]; isDeleted {
+			continue
+		}
+		re := cs.edits[key]
+		var (
+			setClauses []string
+			args       []any
+		)
+		for _, header := range dg.AllHeaders() {
+			edit, ok := re.cells[header]
+			if !ok {
+				continue
+			}
+			args = append(args, edit.NewValue)
+			setClauses = append(setClauses, fmt.Sprintf("%s = $%d", dg.sourceColumnIdent(header), len(args)))
+		}
+		statements = append(statements, Statement{
+			Kind:   StatementUpdate,
+			SQL:    fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, strings.Join(setClauses, ", "), dg.primaryKeyCondition(re.row, &args)),
+			Args:   args,
+			RowKey: key,
+		})
+	}
+
+	for _, key := range cs.insertOrder {
+		var (
+			columns      []string
+			placeholders []string
+			args         []any
+		)
+		// Columns never set are left to their defaults
+		if re, ok := cs.edits[key]; ok {
+			for _, header := range dg.AllHeaders() {
+				edit, ok := re.cells[header]
+				if !ok {
+					continue
+				}
+				args = append(args, edit.NewValue)
+				columns = append(columns, dg.sourceColumnIdent(header))
+				placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+			}
+		}
+		sql := fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", table)
+		if len(columns) > 0 {
+			sql = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+		}
+		statements = append(statements, Statement{Kind: StatementInsert, SQL: sql, Args: args, RowKey: key})
+	}
+
+	return statements
+}
+
+func (dg *DataGrid) sourceColumnIdent(header string) string {
+	name, ok := dg.Source.Columns[header]
+	if !ok || name == "" {
+		name = header
+	}
+	return pgx.Identifier{name}.Sanitize()
+}
+
+func (dg *DataGrid) primaryKeyCondition(row map[string]any, args *[]any) string {
+	conditions := make([]string, len(dg.Source.PrimaryKey))
+	for i, header := range dg.Source.PrimaryKey {
+		*args = append(*args, row[header])
+		conditions[i] = fmt.Sprintf("%s = $%d", dg.sourceColumnIdent(header), len(*args))
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+func valuesEqual(a any, b any) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return format.GetValueAsString(a) == format.GetValueAsString(b)
+}
+
+// Preview renders statement with arguments inlined as literals, for review only
+func (s Statement) Preview() string {
+	return statementPlaceholder.ReplaceAllStringFunc(s.SQL, func(placeholder string) string {
+		idx, err := strconv.Atoi(placeholder[1:])
+		if err != nil || idx < 1 || idx > len(s.Args) {
+			return placeholder
+		}
+		return export.SQLLiteral(s.Args[idx-1])
+	}) + ";"
+}
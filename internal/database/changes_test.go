@@ -0,0 +1,209 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newEditableGrid returns result of `SELECT id, name FROM public."MyTable"` with three rows
+func newEditableGrid() *DataGrid {
+	return &DataGrid{
+		Data: []map[string]any{
+			{"id": int64(1), "name": "a"},
+			{"id": int64(2), "name": "b"},
+			{"id": int64(3), "name": "c"},
+		},
+		Headers: []string{"id", "name"},
+		Rows:    3,
+		Cols:    2,
+		Source: &TableSource{
+			Schema:     "public",
+			Name:       "MyTable",
+			Columns:    map[string]string{"id": "id", "name": "Name"},
+			PrimaryKey: []string{"id"},
+		},
+		Changes: NewChangeSet(),
+	}
+}
+
+func TestStatements(t *testing.T) {
+	tests := []struct {
+		name  string
+		apply func(t *testing.T, dg *DataGrid)
+		want  []Statement
+	}{
+		{
+			name:  "no changes",
+			apply: func(t *testing.T, dg *DataGrid) {},
+			want:  []Statement{},
+		},
+		{
+			name: "edit",
+			apply: func(t *testing.T, dg *DataGrid) {
+				mustSucceed(t, dg.EditCell(0, 1, "x"))
+			},
+			want: []Statement{
+				{Kind: StatementUpdate, SQL: `UPDATE "public"."MyTable" SET "Name" = $1 WHERE "id" = $2`, Args: []any{"x", int64(1)}, RowKey: "1"},
+			},
+		},
+		{
+			name: "edit back to original value",
+			apply: func(t *testing.T, dg *DataGrid) {
+				mustSucceed(t, dg.EditCell(0, 1, "x"))
+				mustSucceed(t, dg.EditCell(0, 1, "a"))
+			},
+			want: []Statement{},
+		},
+		{
+			name: "delete",
+			apply: func(t *testing.T, dg *DataGrid) {
+				mustSucceed(t, dg.ToggleRowDeleted(1))
+			},
+			want: []Statement{
+				{Kind: StatementDelete, SQL: `DELETE FROM "public"."MyTable" WHERE "id" = $1`, Args: []any{int64(2)}, RowKey: "2"},
+			},
+		},
+		{
+			name: "delete twice restores row",
+			apply: func(t *testing.T, dg *DataGrid) {
+				mustSucceed(t, dg.ToggleRowDeleted(1))
+				mustSucceed(t, dg.ToggleRowDeleted(1))
+			},
+			want: []Statement{},
+		},
+		{
+			name: "deleted row is not updated",
+			apply: func(t *testing.T, dg *DataGrid) {
+				mustSucceed(t, dg.EditCell(2, 1, "x"))
+				mustSucceed(t, dg.ToggleRowDeleted(2))
+			},
+			want: []Statement{
+				{Kind: StatementDelete, SQL: `DELETE FROM "public"."MyTable" WHERE "id" = $1`, Args: []any{int64(3)}, RowKey: "3"},
+			},
+		},
+		{
+			name: "insert without values",
+			apply: func(t *testing.T, dg *DataGrid) {
+				mustSucceed(t, dg.InsertRow(3))
+			},
+			want: []Statement{
+				{Kind: StatementInsert, SQL: `INSERT INTO "public"."MyTable" DEFAULT VALUES`, RowKey: "+1"},
+			},
+		},
+		{
+			name: "insert with values",
+			apply: func(t *testing.T, dg *DataGrid) {
+				mustSucceed(t, dg.InsertRow(0))
+				mustSucceed(t, dg.EditCell(0, 1, "new"))
+				mustSucceed(t, dg.EditCell(0, 0, "4"))
+			},
+			want: []Statement{
+				{Kind: StatementInsert, SQL: `INSERT INTO "public"."MyTable" ("id", "Name") VALUES ($1, $2)`, Args: []any{"4", "new"}, RowKey: "+1"},
+			},
+		},
+		{
+			name: "deletes go before updates and inserts",
+			apply: func(t *testing.T, dg *DataGrid) {
+				mustSucceed(t, dg.InsertRow(3))
+				mustSucceed(t, dg.EditCell(0, 1, "x"))
+				mustSucceed(t, dg.ToggleRowDeleted(1))
+			},
+			want: []Statement{
+				{Kind: StatementDelete, SQL: `DELETE FROM "public"."MyTable" WHERE "id" = $1`, Args: []any{int64(2)}, RowKey: "2"},
+				{Kind: StatementUpdate, SQL: `UPDATE "public"."MyTable" SET "Name" = $1 WHERE "id" = $2`, Args: []any{"x", int64(1)}, RowKey: "1"},
+				{Kind: StatementInsert, SQL: `INSERT INTO "public"."MyTable" DEFAULT VALUES`, RowKey: "+1"},
+			},
+		},
+		{
+			name: "undo of edit",
+			apply: func(t *testing.T, dg *DataGrid) {
+				mustSucceed(t, dg.EditCell(0, 1, "x"))
+				if !dg.Undo() {
+					t.Fatal("nothing to undo")
+				}
+				if got := dg.Data[0]["name"]; got != "a" {
+					t.Errorf("undo restored %v, want a", got)
+				}
+			},
+			want: []Statement{},
+		},
+		{
+			name: "removing inserted row",
+			apply: func(t *testing.T, dg *DataGrid) {
+				mustSucceed(t, dg.InsertRow(3))
+				mustSucceed(t, dg.ToggleRowDeleted(3))
+				if dg.Rows != 3 {
+					t.Errorf("grid has %d rows, want 3", dg.Rows)
+				}
+			},
+			want: []Statement{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dg := newEditableGrid()
+			tt.apply(t, dg)
+			if got := dg.Statements(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Statements() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditCellRejectsPrimaryKey(t *testing.T) {
+	dg := newEditableGrid()
+	if err := dg.EditCell(0, 0, "5"); err == nil {
+		t.Error("editing primary key of existing row succeeded")
+	}
+	if err := dg.ToggleRowDeleted(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := dg.EditCell(0, 1, "x"); err == nil {
+		t.Error("editing row marked for deletion succeeded")
+	}
+}
+
+func TestStatementsOfReadOnlyGrid(t *testing.T) {
+	dg := newEditableGrid()
+	dg.Source = nil
+	if err := dg.EditCell(0, 1, "x"); err == nil {
+		t.Error("editing grid without source table succeeded")
+	}
+	if got := dg.Statements(); got != nil {
+		t.Errorf("Statements() = %v, want nil", got)
+	}
+}
+
+func TestRevisionChangesWithEveryEdit(t *testing.T) {
+	dg := newEditableGrid()
+	revision := dg.Changes.Revision()
+	mustSucceed(t, dg.EditCell(0, 1, "x"))
+	afterFirst := dg.Changes.Revision()
+	mustSucceed(t, dg.EditCell(0, 1, "y"))
+	if afterFirst == revision || dg.Changes.Revision() == afterFirst {
+		t.Errorf("revisions %d, %d, %d are not distinct", revision, afterFirst, dg.Changes.Revision())
+	}
+	if dg.Changes.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", dg.Changes.Len())
+	}
+}
+
+func TestApplyCommitted(t *testing.T) {
+	dg := newEditableGrid()
+	mustSucceed(t, dg.ToggleRowDeleted(1))
+	mustSucceed(t, dg.EditCell(0, 1, "x"))
+	dg.ApplyCommitted()
+	if dg.Rows != 2 || dg.Data[1]["id"] != int64(3) {
+		t.Errorf("rows after commit %v, want rows with id 1 and 3", dg.Data)
+	}
+	if dg.Changes.Len() != 0 {
+		t.Errorf("%d changes are pending after commit", dg.Changes.Len())
+	}
+}
+
+func mustSucceed(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,184 @@
+package database
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/quar15/qq-go/internal/format"
+)
+
+// chartMaxPoints limits points of chart, bigger results are sampled evenly
+const chartMaxPoints int = 2000
+
+type ChartKind int8
+
+const (
+	ChartBar ChartKind = iota
+	ChartLine
+	ChartScatter
+)
+
+var chartKindName = map[ChartKind]string{
+	ChartBar:     "bar",
+	ChartLine:    "line",
+	ChartScatter: "scatter",
+}
+
+func (k ChartKind) String() string {
+	return chartKindName[k]
+}
+
+func ParseChartKind(name string) (ChartKind, error) {
+	for kind, kindName := range chartKindName {
+		if strings.EqualFold(name, kindName) {
+			return kind, nil
+		}
+	}
+	return ChartBar, fmt.Errorf("Unknown chart kind: %s (bar, line or scatter)", name)
+}
+
+// ChartSpec describes chart of result: column of X axis (categories) and numeric columns drawn as series
+type ChartSpec struct {
+	Kind    ChartKind
+	XColumn string
+	Series  []string
+}
+
+type ChartSeries struct {
+	Name   string
+	Values []float64
+	Valid  []bool // false for NULL and non numeric values, such points are skipped
+}
+
+// ChartData holds points of chart, X values are numeric only for scatter charts with numeric X column
+type ChartData struct {
+	Labels   []string
+	X        []float64
+	XNumeric bool
+	Series   []ChartSeries
+	MinX     float64
+	MaxX     float64
+	MinY     float64
+	MaxY     float64
+	Sampled  bool
+}
+
+// BuildChartData reads points of chart from displayed rows of the grid
+func BuildChartData(dg *DataGrid, spec ChartSpec) (ChartData, error) {
+	headers := dg.AllHeaders()
+	if !slices.Contains(headers, spec.XColumn) {
+		return ChartData{}, fmt.Errorf("Column '%s' is not in result", spec.XColumn)
+	}
+	if len(spec.Series) == 0 {
+		return ChartData{}, fmt.Errorf("No numeric column to chart")
+	}
+	for _, name := range spec.Series {
+		if !slices.Contains(headers, name) {
+			return ChartData{}, fmt.Errorf("Column '%s' is not in result", name)
+		}
+	}
+
+	rows := dg.Data
+	data := ChartData{
+		MinX: math.Inf(1), MaxX: math.Inf(-1),
+		MinY: math.Inf(1), MaxY: math.Inf(-1),
+	}
+	if len(rows) > chartMaxPoints {
+		sampled := make([]map[string]any, 0, chartMaxPoints)
+		for i := range chartMaxPoints {
+			sampled = append(sampled, rows[i*len(rows)/chartMaxPoints])
+		}
+		rows = sampled
+		data.Sampled = true
+	}
+
+	data.XNumeric = spec.Kind == ChartScatter
+	data.Labels = make([]string, len(rows))
+	data.X = make([]float64, len(rows))
+	for i, row := range rows {
+		data.Labels[i] = format.GetDisplayValue(row[spec.XColumn])
+		x, ok := chartValue(row[spec.XColumn])
+		if !ok {
+			data.XNumeric = false
+		}
+		data.X[i] = x
+	}
+	if !data.XNumeric {
+		for i := range data.X {
+			data.X[i] = float64(i)
+		}
+	}
+	for _, x := range data.X {
+		data.MinX, data.MaxX = math.Min(data.MinX, x), math.Max(data.MaxX, x)
+	}
+
+	for _, name := range spec.Series {
+		series := ChartSeries{Name: name, Values: make([]float64, len(rows)), Valid: make([]bool, len(rows))}
+		for i, row := range rows {
+			y, ok := chartValue(row[name])
+			if !ok {
+				continue
+			}
+			series.Values[i], series.Valid[i] = y, true
+			data.MinY, data.MaxY = math.Min(data.MinY, y), math.Max(data.MaxY, y)
+		}
+		data.Series = append(data.Series, series)
+	}
+	if math.IsInf(data.MinY, 0) {
+		return ChartData{}, fmt.Errorf("No numeric values in %s", strings.Join(spec.Series, ", "))
+	}
+	if spec.Kind == ChartBar {
+		// Bars grow from zero
+		data.MinY, data.MaxY = math.Min(data.MinY, 0), math.Max(data.MaxY, 0)
+	}
+	return data, nil
+}
+
+// NumericColumns returns visible columns whose sampled non NULL values are all numeric, except given column
+func NumericColumns(dg *DataGrid, except string) []string {
+	const sampleSize int = 100
+	var columns []string
+	for _, header := range dg.Headers {
+		if header == except {
+			continue
+		}
+		var numeric, other int
+		for _, row := range dg.Data[:min(len(dg.Data), sampleSize)] {
+			if row[header] == nil {
+				continue
+			}
+			if _, ok := numericValue(row[header]); ok {
+				numeric++
+			} else {
+				other++
+			}
+		}
+		if numeric > 0 && other == 0 {
+			columns = append(columns, header)
+		}
+	}
+	return columns
+}
+
+// chartValue converts value to position on axis, time is converted to unix seconds
+func chartValue(val any) (float64, bool) {
+	if t, ok := val.(time.Time); ok {
+		return float64(t.Unix()), true
+	}
+	if d, ok := val.(pgtype.Date); ok && d.Valid && d.InfinityModifier == pgtype.Finite {
+		return float64(d.Time.Unix()), true
+	}
+	if ts, ok := val.(pgtype.Timestamp); ok && ts.Valid && ts.InfinityModifier == pgtype.Finite {
+		return float64(ts.Time.Unix()), true
+	}
+	n, ok := numericValue(val)
+	if !ok {
+		return 0, false
+	}
+	f, _ := n.Float64()
+	return f, true
+}
@@ -0,0 +1,143 @@
+package database
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const (
+	columnWidthsPath       string = "./config/column_widths.json"
+	MinimumColumnWidth     int32  = 50
+	MaximumColumnWidth     int32  = 4000
+	columnWidthTextPadding int32  = 8
+	fallbackCharacterWidth int32  = 8
+)
+
+// columnWidthsStore keeps widths set by user per query or table, widths are keyed by header
+type columnWidthsStore struct {
+	mu     sync.Mutex
+	loaded bool
+	widths map[string]map[string]int32
+}
+
+var savedColumnWidths columnWidthsStore
+
+func (s *columnWidthsStore) load() {
+	if s.loaded {
+		return
+	}
+	s.loaded = true
+	s.widths = make(map[string]map[string]int32)
+	data, err := os.ReadFile(columnWidthsPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Warn("Failed to read saved column widths", slog.String("path", columnWidthsPath), slog.Any("error", err))
+		}
+		return
+	}
+	if err := json.Unmarshal(data, &s.widths); err != nil {
+		slog.Warn("Failed to parse saved column widths", slog.String("path", columnWidthsPath), slog.Any("error", err))
+		s.widths = make(map[string]map[string]int32)
+	}
+}
+
+func (s *columnWidthsStore) get(key string) map[string]int32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.load()
+	return s.widths[key]
+}
+
+func (s *columnWidthsStore) set(key string, widths map[string]int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.load()
+	s.widths[key] = widths
+
+	data, err := json.MarshalIndent(s.widths, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(columnWidthsPath), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(columnWidthsPath, data, 0o644)
+}
+
+// ColumnWidthsKey identifies result for remembering column widths, empty when result has no stable origin
+func (dg *DataGrid) ColumnWidthsKey() string {
+	if dg.Source != nil {
+		return "table:" + dg.Source.String()
+	}
+	if query := strings.Join(strings.Fields(dg.Query), " "); query != "" {
+		return "query:" + strings.TrimSuffix(query, ";")
+	}
+	return ""
+}
+
+// applySavedColumnsWidth overrides measured widths with ones remembered from previous runs
+func (dg *DataGrid) applySavedColumnsWidth() {
+	key := dg.ColumnWidthsKey()
+	if key == "" {
+		return
+	}
+	saved := savedColumnWidths.get(key)
+	for i, h := range dg.Headers {
+		if width, ok := saved[h]; ok {
+			dg.ColumnsWidth[i] = min(max(width, MinimumColumnWidth), MaximumColumnWidth)
+		}
+	}
+}
+
+// SaveColumnsWidth remembers current widths of all columns for next runs of the same query or table
+func (dg *DataGrid) SaveColumnsWidth() error {
+	key := dg.ColumnWidthsKey()
+	if key == "" || len(dg.ColumnsWidth) != len(dg.Headers) {
+		return nil
+	}
+	widths := make(map[string]int32, len(dg.Headers))
+	for i, h := range dg.Headers {
+		widths[h] = dg.ColumnsWidth[i]
+	}
+	return savedColumnWidths.set(key, widths)
+}
+
+// ResizeColumn sets width of column clamped to allowed range, width is not saved
+func (dg *DataGrid) ResizeColumn(col int32, width int32) {
+	if col < 0 || int(col) >= len(dg.ColumnsWidth) {
+		return
+	}
+	dg.ColumnsWidth[col] = min(max(width, MinimumColumnWidth), MaximumColumnWidth)
+}
+
+// FitColumnWidth sets width of column to its widest header or displayed value of sampled rows, width is not saved
+func (dg *DataGrid) FitColumnWidth(col int32) {
+	if col < 0 || int(col) >= len(dg.ColumnsWidth) || col >= int32(len(dg.Headers)) {
+		return
+	}
+	header := dg.Headers[col]
+	var width int32 = dg.measureText(header)
+	for _, row := range dg.sampleRows() {
+		width = max(width, dg.measureText(displayCellText(row[header])))
+	}
+	dg.ResizeColumn(col, width+columnWidthTextPadding*3)
+}
+
+func (dg *DataGrid) FitAllColumnsWidth() {
+	for col := range int32(len(dg.ColumnsWidth)) {
+		dg.FitColumnWidth(col)
+	}
+}
+
+// measureText returns width of text in pixels, falls back to character count before fonts were measured
+func (dg *DataGrid) measureText(text string) int32 {
+	if dg.textMeasure != nil {
+		return dg.textMeasure(text)
+	}
+	return int32(utf8.RuneCountInString(text)) * fallbackCharacterWidth
+}
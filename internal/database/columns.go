@@ -0,0 +1,162 @@
+package database
+
+import (
+	"fmt"
+	"slices"
+)
+
+// columnView keeps order and visibility of result columns, Headers, ColumnTypes and ColumnsWidth
+// of DataGrid are rebuilt from it, while rows stay untouched
+type columnView struct {
+	original []string // headers in order returned by query
+	order    []string // headers in display order, including hidden ones
+	hidden   map[string]bool
+	types    map[string]string
+	widths   map[string]int32
+}
+
+// AllHeaders returns every column of the result in original order, regardless of hidden and moved columns
+func (dg *DataGrid) AllHeaders() []string {
+	if dg.columns != nil {
+		return dg.columns.original
+	}
+	return dg.Headers
+}
+
+// ColumnType returns postgres type of column, also of hidden one. It is empty when grid does not come from query.
+func (dg *DataGrid) ColumnType(header string) string {
+	if dg.columns != nil {
+		return dg.columns.types[header]
+	}
+	if idx := slices.Index(dg.Headers, header); idx >= 0 && idx < len(dg.ColumnTypes) {
+		return dg.ColumnTypes[idx]
+	}
+	return ""
+}
+
+// HiddenHeaders returns hidden columns in display order
+func (dg *DataGrid) HiddenHeaders() []string {
+	if dg.columns == nil {
+		return nil
+	}
+	var hidden []string
+	for _, header := range dg.columns.order {
+		if dg.columns.hidden[header] {
+			hidden = append(hidden, header)
+		}
+	}
+	return hidden
+}
+
+// HideColumn removes column from view, at least one column always stays visible
+func (dg *DataGrid) HideColumn(header string) error {
+	if !slices.Contains(dg.Headers, header) {
+		return fmt.Errorf("Unknown column '%s'", header)
+	}
+	if dg.Cols <= 1 {
+		return fmt.Errorf("Cannot hide last visible column")
+	}
+	dg.ensureColumnView()
+	dg.columns.hidden[header] = true
+	if idx := slices.Index(dg.Headers, header); int32(idx) < dg.FrozenCols {
+		dg.FrozenCols--
+	}
+	dg.applyColumnView()
+	return nil
+}
+
+// UnhideColumns shows given hidden columns again, without headers all hidden columns are shown
+func (dg *DataGrid) UnhideColumns(headers ...string) error {
+	if dg.columns == nil {
+		return nil
+	}
+	dg.ensureColumnView()
+	if len(headers) == 0 {
+		headers = dg.HiddenHeaders()
+	}
+	for _, header := range headers {
+		if !slices.Contains(dg.columns.original, header) {
+			return fmt.Errorf("Unknown column '%s'", header)
+		}
+		delete(dg.columns.hidden, header)
+	}
+	dg.applyColumnView()
+	return nil
+}
+
+// MoveColumn swaps visible column with its visible neighbour, returns new index of the column
+func (dg *DataGrid) MoveColumn(col int32, offset int32) int32 {
+	target := col + offset
+	if col < 0 || col >= dg.Cols || target < 0 || target >= dg.Cols {
+		return col
+	}
+	dg.ensureColumnView()
+	from := slices.Index(dg.columns.order, dg.Headers[col])
+	to := slices.Index(dg.columns.order, dg.Headers[target])
+	dg.columns.order[from], dg.columns.order[to] = dg.columns.order[to], dg.columns.order[from]
+	dg.applyColumnView()
+	return target
+}
+
+// FreezeColumns keeps first n visible columns in place while scrolling horizontally, 0 unfreezes
+func (dg *DataGrid) FreezeColumns(n int32) {
+	dg.FrozenCols = min(max(n, 0), dg.Cols)
+}
+
+// ResetColumns restores original order and visibility of columns
+func (dg *DataGrid) ResetColumns() {
+	if dg.columns == nil {
+		return
+	}
+	dg.ensureColumnView()
+	dg.columns.order = slices.Clone(dg.columns.original)
+	clear(dg.columns.hidden)
+	dg.FrozenCols = 0
+	dg.applyColumnView()
+}
+
+// IsColumnViewChanged reports if columns are hidden, moved or frozen
+func (dg *DataGrid) IsColumnViewChanged() bool {
+	if dg.FrozenCols > 0 {
+		return true
+	}
+	return dg.columns != nil && (len(dg.columns.hidden) > 0 || !slices.Equal(dg.columns.order, dg.columns.original))
+}
+
+// ensureColumnView starts tracking columns, or remembers current widths and types of visible ones
+func (dg *DataGrid) ensureColumnView() {
+	if dg.columns == nil {
+		dg.columns = &columnView{
+			original: slices.Clone(dg.Headers),
+			order:    slices.Clone(dg.Headers),
+			hidden:   make(map[string]bool),
+			types:    make(map[string]string),
+			widths:   make(map[string]int32),
+		}
+	}
+	for i, header := range dg.Headers {
+		if i < len(dg.ColumnTypes) {
+			dg.columns.types[header] = dg.ColumnTypes[i]
+		}
+		if i < len(dg.ColumnsWidth) {
+			dg.columns.widths[header] = dg.ColumnsWidth[i]
+		}
+	}
+}
+
+func (dg *DataGrid) applyColumnView() {
+	cv := dg.columns
+	dg.Headers = make([]string, 0, len(cv.order))
+	dg.ColumnTypes = make([]string, 0, len(cv.order))
+	dg.ColumnsWidth = make([]int32, 0, len(cv.order))
+	for _, header := range cv.order {
+		if cv.hidden[header] {
+			continue
+		}
+		dg.Headers = append(dg.Headers, header)
+		dg.ColumnTypes = append(dg.ColumnTypes, cv.types[header])
+		dg.ColumnsWidth = append(dg.ColumnsWidth, max(cv.widths[header], MinimumColumnWidth))
+	}
+	dg.Cols = int32(len(dg.Headers))
+	dg.FrozenCols = min(dg.FrozenCols, dg.Cols)
+}
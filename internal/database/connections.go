@@ -51,8 +51,10 @@ func (c *ConnectionData) CheckForQueryResult() (dg *DataGrid, done bool, err err
 			c.ClearConn()
 			return nil, true, err
 		}
-		slog.Debug("Query result", slog.Any("res", res))
+		slog.Debug("Query result", slog.Int("rows", int(res.Results.Rows)), slog.Int("cols", int(res.Results.Cols)))
 		*dg = *res.Results
+		dg.ConnectionName = c.Name
+		dg.Query = c.QueryText
 		c.ClearQuery()
 		return dg, true, nil
 	// Query still running
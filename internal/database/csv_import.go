@@ -0,0 +1,364 @@
+package database
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/quar15/qq-go/internal/format"
+)
+
+const defaultImportBatchSize int = 1000
+
+var importTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+type ImportOptions struct {
+	Table       string
+	CreateTable bool
+	BatchSize   int
+}
+
+type ImportRowError struct {
+	Row    int // 1-based row number in the grid (without header)
+	Column string
+	Value  string
+	Err    string
+}
+
+type ImportProgress struct {
+	Total      int
+	Imported   int
+	Failed     int
+	Done       bool
+	Err        error
+	ReportPath string
+}
+
+type importColumn struct {
+	Header   string
+	Name     string
+	TypeName string
+}
+
+// ImportDataGrid streams content of the grid into table of current connection using COPY FROM STDIN.
+// Import runs on dedicated connection and reports progress through returned channel.
+func (mgr *ConnectionManager) ImportDataGrid(dg *DataGrid, opts ImportOptions) (<-chan ImportProgress, error) {
+	mgr.mu.RLock()
+	connData := mgr.current
+	mgr.mu.RUnlock()
+
+	if connData == nil {
+		return nil, errors.New("No connection selected")
+	}
+	if connData.Driver != "postgresql" {
+		return nil, fmt.Errorf("Import is not supported by driver: %s", connData.Driver)
+	}
+	if dg == nil || dg.Rows == 0 {
+		return nil, errors.New("Nothing to import")
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultImportBatchSize
+	}
+
+	// Snapshot grid, so new query results do not interfere with running import
+	headers := slices.Clone(dg.AllHeaders())
+	rows := slices.Clone(dg.Data)
+	columnTypes := make(map[string]string, len(headers))
+	for _, header := range headers {
+		columnTypes[header] = dg.ColumnType(header)
+	}
+
+	progress := make(chan ImportProgress, 1)
+	go func() {
+		defer close(progress)
+		ctx := context.Background()
+
+		conn, err := connectToPostgres(connData.ConnString)
+		if err != nil {
+			progress <- ImportProgress{Total: len(rows), Done: true, Err: err}
+			return
+		}
+		defer conn.Close(ctx)
+
+		result := importRows(ctx, conn, headers, columnTypes, rows, opts, progress)
+		progress <- result
+	}()
+
+	return progress, nil
+}
+
+func importRows(ctx context.Context, conn *pgx.Conn, headers []string, columnTypes map[string]string, rows []map[string]any, opts ImportOptions, progress chan<- ImportProgress) ImportProgress {
+	result := ImportProgress{Total: len(rows), Done: true}
+	tableIdent := pgx.Identifier(strings.Split(opts.Table, "."))
+
+	if opts.CreateTable {
+		createStatement := buildCreateTableStatement(tableIdent, headers, columnTypes, rows)
+		slog.Debug("Creating table for import", slog.String("statement", createStatement))
+		if _, err := conn.Exec(ctx, createStatement); err != nil {
+			result.Err = err
+			return result
+		}
+	}
+
+	columns, err := mapImportColumns(ctx, conn, tableIdent, headers)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	columnNames := make([]string, len(columns))
+	for i, col := range columns {
+		columnNames[i] = col.Name
+	}
+
+	var report []ImportRowError
+	for batchStart := 0; batchStart < len(rows); batchStart += opts.BatchSize {
+		batchEnd := min(batchStart+opts.BatchSize, len(rows))
+
+		batch := make([][]any, 0, batchEnd-batchStart)
+		batchRowNumbers := make([]int, 0, batchEnd-batchStart)
+		for i := batchStart; i < batchEnd; i++ {
+			values, rowErr := convertImportRow(rows[i], columns)
+			if rowErr != nil {
+				rowErr.Row = i + 1
+				report = append(report, *rowErr)
+				result.Failed++
+				continue
+			}
+			batch = append(batch, values)
+			batchRowNumbers = append(batchRowNumbers, i+1)
+		}
+
+		_, err := conn.CopyFrom(ctx, tableIdent, columnNames, pgx.CopyFromRows(batch))
+		if err == nil {
+			result.Imported += len(batch)
+		} else {
+			// COPY is atomic, retry rows one by one to find the offending ones
+			slog.Warn("Import batch failed, retrying row by row", slog.Int("batchStart", batchStart), slog.Any("error", err))
+			for i, values := range batch {
+				_, rowErr := conn.CopyFrom(ctx, tableIdent, columnNames, pgx.CopyFromRows([][]any{values}))
+				if rowErr != nil {
+					report = append(report, ImportRowError{Row: batchRowNumbers[i], Err: rowErr.Error()})
+					result.Failed++
+					continue
+				}
+				result.Imported++
+			}
+		}
+
+		select {
+		case progress <- ImportProgress{Total: result.Total, Imported: result.Imported, Failed: result.Failed}:
+		default:
+		}
+	}
+
+	if len(report) > 0 {
+		reportPath := fmt.Sprintf("import_errors_%s_%d.csv", strings.ReplaceAll(opts.Table, ".", "_"), time.Now().Unix())
+		if err := writeImportReport(reportPath, report); err != nil {
+			slog.Error("Failed to write import report", slog.String("path", reportPath), slog.Any("error", err))
+		} else {
+			result.ReportPath = reportPath
+		}
+	}
+
+	slog.Info("Import finished", slog.String("table", opts.Table), slog.Int("imported", result.Imported), slog.Int("failed", result.Failed))
+	return result
+}
+
+// mapImportColumns matches grid headers with columns of existing table (case-insensitive). Table is looked up by
+// the same quoted name which COPY uses, so mixed case name is not folded to lower case.
+func mapImportColumns(ctx context.Context, conn *pgx.Conn, tableIdent pgx.Identifier, headers []string) ([]importColumn, error) {
+	const columnsQuery string = `
+		SELECT a.attname, t.typname
+		FROM pg_attribute a
+		JOIN pg_type t ON t.oid = a.atttypid
+		WHERE a.attrelid = $1::regclass AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY a.attnum`
+
+	table := tableIdent.Sanitize()
+	rows, err := conn.Query(ctx, columnsQuery, table)
+	if err != nil {
+		return nil, err
+	}
+	tableColumns := map[string]importColumn{}
+	for rows.Next() {
+		var col importColumn
+		if err := rows.Scan(&col.Name, &col.TypeName); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		tableColumns[strings.ToLower(col.Name)] = col
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var columns []importColumn
+	for _, h := range headers {
+		col, ok := tableColumns[strings.ToLower(strings.TrimSpace(h))]
+		if !ok {
+			slog.Warn("Skipping CSV column without matching table column", slog.String("header", h), slog.String("table", table))
+			continue
+		}
+		col.Header = h
+		columns = append(columns, col)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("No CSV header matches columns of '%s'", table)
+	}
+
+	return columns, nil
+}
+
+func convertImportRow(row map[string]any, columns []importColumn) ([]any, *ImportRowError) {
+	values := make([]any, len(columns))
+	for i, col := range columns {
+		raw := importText(row[col.Header])
+		val, err := convertImportValue(raw, col.TypeName)
+		if err != nil {
+			return nil, &ImportRowError{Column: col.Name, Value: raw, Err: err.Error()}
+		}
+		values[i] = val
+	}
+	return values, nil
+}
+
+// importText returns cell as CSV text. Grids of query results hold typed values, they are converted through their
+// text like CSV cells, only NULL stays empty.
+func importText(val any) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return format.GetValueAsString(v)
+	}
+}
+
+// convertImportValue parses CSV text into value accepted by binary COPY for given postgres type. Empty text is NULL.
+func convertImportValue(raw string, typeName string) (any, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	switch typeName {
+	case "int2", "int4", "int8":
+		return strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	case "float4", "float8":
+		return strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	case "numeric":
+		var n pgtype.Numeric
+		if err := n.Scan(strings.TrimSpace(raw)); err != nil {
+			return nil, err
+		}
+		return n, nil
+	case "bool":
+		return parseImportBool(raw)
+	case "date", "timestamp", "timestamptz":
+		return parseImportTime(raw)
+	case "uuid":
+		var u pgtype.UUID
+		if err := u.Scan(strings.TrimSpace(raw)); err != nil {
+			return nil, err
+		}
+		return u, nil
+	default:
+		return raw, nil
+	}
+}
+
+func parseImportBool(raw string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "t", "true", "y", "yes", "on", "1":
+		return true, nil
+	case "f", "false", "n", "no", "off", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean: %s", raw)
+}
+
+func parseImportTime(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	for _, layout := range importTimeLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date/time: %s", raw)
+}
+
+// inferImportType picks the narrowest postgres type accepting every non-empty value of the column
+func inferImportType(header string, rows []map[string]any) string {
+	candidates := []string{"int8", "float8", "bool", "date", "timestamptz"}
+	seenValue := false
+	for _, row := range rows {
+		raw := importText(row[header])
+		if raw == "" {
+			continue
+		}
+		seenValue = true
+		candidates = slices.DeleteFunc(candidates, func(typeName string) bool {
+			if typeName == "date" {
+				_, err := time.Parse("2006-01-02", strings.TrimSpace(raw))
+				return err != nil
+			}
+			_, err := convertImportValue(raw, typeName)
+			return err != nil
+		})
+		if len(candidates) == 0 {
+			break
+		}
+	}
+	if !seenValue || len(candidates) == 0 {
+		return "text"
+	}
+	return candidates[0]
+}
+
+// buildCreateTableStatement keeps types of query result columns, types of CSV columns are inferred from values
+func buildCreateTableStatement(table pgx.Identifier, headers []string, columnTypes map[string]string, rows []map[string]any) string {
+	columnDefinitions := make([]string, len(headers))
+	for i, h := range headers {
+		typeName := columnTypes[h]
+		if typeName == "" {
+			typeName = inferImportType(h, rows)
+		}
+		columnDefinitions[i] = fmt.Sprintf("%s %s", pgx.Identifier{h}.Sanitize(), typeName)
+	}
+	return fmt.Sprintf("CREATE TABLE %s (%s)", table.Sanitize(), strings.Join(columnDefinitions, ", "))
+}
+
+func writeImportReport(path string, report []ImportRowError) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if err := w.Write([]string{"row", "column", "value", "error"}); err != nil {
+		return err
+	}
+	for _, rowErr := range report {
+		if err := w.Write([]string{strconv.Itoa(rowErr.Row), rowErr.Column, rowErr.Value, rowErr.Err}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
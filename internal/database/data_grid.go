@@ -4,18 +4,58 @@ import (
 	"encoding/csv"
 	"errors"
 	"os"
+	"slices"
+	"unicode/utf8"
 
-	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/jackc/pgx/v5"
 	"github.com/quar15/qq-go/internal/assets"
 	"github.com/quar15/qq-go/internal/format"
 )
 
 type DataGrid struct {
-	Data         []map[string]any
-	Headers      []string
-	ColumnsWidth []int32
-	Rows         int32
-	Cols         int32
+	Data           []map[string]any
+	Headers        []string
+	ColumnsWidth   []int32
+	Rows           int32
+	Cols           int32
+	ColumnTypes    []string
+	ConnectionName string
+	Query          string // query text which produced result, empty for loaded files
+	Source         *TableSource
+	Changes        *ChangeSet
+	SortKeys       []SortKey
+	Filter         *RowFilter
+	FrozenCols     int32                  // number of leading visible columns kept in place while scrolling horizontally
+	allData        []map[string]any       // original order of rows while view is sorted or filtered
+	columns        *columnView            // order and visibility of columns, nil until view is changed
+	diff           map[uintptr]DiffStatus // status of rows of diff result by row identity
+	textMeasure    func(text string) int32
+}
+
+// TableSource describes single table from which all columns of the result come
+type TableSource struct {
+	Schema     string
+	Name       string            // unquoted, Identifier quotes it for statements
+	Columns    map[string]string // header -> table column name
+	PrimaryKey []string          // headers of primary key columns
+}
+
+// Identifier returns schema qualified name of source table, Sanitize of it is safe to use in statements
+func (s *TableSource) Identifier() pgx.Identifier {
+	return pgx.Identifier{s.Schema, s.Name}
+}
+
+func (s *TableSource) String() string {
+	return s.Schema + "." + s.Name
+}
+
+// IsEditable reports if result comes from single table and contains its whole primary key
+func (dg *DataGrid) IsEditable() bool {
+	return dg.Source != nil && len(dg.Source.PrimaryKey) > 0 && dg.Changes != nil
+}
+
+func (dg *DataGrid) IsPrimaryKeyColumn(header string) bool {
+	return dg.Source != nil && slices.Contains(dg.Source.PrimaryKey, header)
 }
 
 func (dg *DataGrid) FakeInit(appAssets *assets.Assets) {
@@ -30,24 +70,49 @@ func (dg *DataGrid) FakeInit(appAssets *assets.Assets) {
 	dg.UpdateColumnsWidth(appAssets)
 }
 
+// UpdateColumnsWidth computes widths from header and sample of rows, fonts are monospace so text width is count of characters
 func (dg *DataGrid) UpdateColumnsWidth(appAssets *assets.Assets) {
+	var characterWidth float32 = appAssets.MainFontCharacterWidth
+	dg.textMeasure = func(text string) int32 {
+		return int32(float32(utf8.RuneCountInString(text)) * characterWidth)
+	}
+	dg.computeColumnsWidth()
+}
+
+// computeColumnsWidth measures columns with text measure of the grid, used also for results derived from other grids
+func (dg *DataGrid) computeColumnsWidth() {
 	const maximumColWidth int32 = 600
-	const minimumColWidth int32 = 50
-	const textPadding int32 = 8
 	dg.ColumnsWidth = nil
 	dg.ColumnsWidth = make([]int32, len(dg.Headers))
 	for i, h := range dg.Headers {
-		var headerWidth int32 = int32(rl.MeasureTextEx(appAssets.MainFont, h, appAssets.MainFontSize, appAssets.MainFontSpacing).X) + (textPadding * 3)
-		headerWidth = min(max(headerWidth, minimumColWidth), maximumColWidth)
-		for row := range dg.Rows {
-			val := dg.Data[row][h]
-			var textWidth int32 = int32(rl.MeasureTextEx(appAssets.MainFont, format.GetValueAsString(val), appAssets.MainFontSize, appAssets.MainFontSpacing).X) + (textPadding * 3)
+		var headerWidth int32 = dg.measureText(h) + (columnWidthTextPadding * 3)
+		headerWidth = min(max(headerWidth, MinimumColumnWidth), maximumColWidth)
+		for _, row := range dg.sampleRows() {
+			var textWidth int32 = dg.measureText(displayCellText(row[h])) + (columnWidthTextPadding * 3)
 			if textWidth > headerWidth {
 				headerWidth = textWidth
 			}
 		}
 		dg.ColumnsWidth[i] = min(headerWidth, maximumColWidth)
 	}
+	dg.applySavedColumnsWidth()
+}
+
+// sampleRows returns rows spread evenly over the result, all rows of small results
+func (dg *DataGrid) sampleRows() []map[string]any {
+	const sampleSize int = 1000
+	if len(dg.Data) <= sampleSize {
+		return dg.Data
+	}
+	sample := make([]map[string]any, 0, sampleSize)
+	for i := range sampleSize {
+		sample = append(sample, dg.Data[i*len(dg.Data)/sampleSize])
+	}
+	return sample
+}
+
+func displayCellText(val any) string {
+	return format.TruncateCell(format.GetDisplayValue(val))
 }
 
 func LoadDataGridFromCSV(path string, appAssets *assets.Assets) (*DataGrid, error) {
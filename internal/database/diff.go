@@ -0,0 +1,199 @@
+package database
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/quar15/qq-go/internal/format"
+)
+
+// DiffMarkerHeader is first column of diff result, it holds "+", "-" or "~" of the row.
+// When compared results have column of that name, suffix is added to keep it distinct.
+const DiffMarkerHeader string = "diff"
+
+type DiffStatus int8
+
+const (
+	DiffNone DiffStatus = iota
+	DiffAdded
+	DiffRemoved
+	DiffChanged
+)
+
+var diffMarker = map[DiffStatus]string{
+	DiffAdded:   "+",
+	DiffRemoved: "-",
+	DiffChanged: "~",
+}
+
+// CellChange is value of changed cell in diff result
+type CellChange struct {
+	Old any
+	New any
+}
+
+func (c CellChange) String() string {
+	return format.GetDisplayValue(c.Old) + " -> " + format.GetDisplayValue(c.New)
+}
+
+type DiffSummary struct {
+	Added     int
+	Removed   int
+	Changed   int
+	Unchanged int
+}
+
+func (s DiffSummary) String() string {
+	return fmt.Sprintf("+%d -%d ~%d (%d unchanged)", s.Added, s.Removed, s.Changed, s.Unchanged)
+}
+
+// DiffStatus returns status of row in diff result, DiffNone for rows of ordinary results
+func (dg *DataGrid) DiffStatus(row int32) DiffStatus {
+	if dg.diff == nil || row < 0 || row >= dg.Rows {
+		return DiffNone
+	}
+	return dg.diff[rowIdentity(dg.Data[row])]
+}
+
+// DiffGrids compares old and new result. Rows are matched by key columns, or by equality of whole rows when no key is given.
+// Result contains only added, removed and changed rows, changed cells hold CellChange.
+func DiffGrids(oldGrid *DataGrid, newGrid *DataGrid, keys []string) (*DataGrid, DiffSummary, error) {
+	headers := slices.Clone(oldGrid.AllHeaders())
+	for _, header := range newGrid.AllHeaders() {
+		if !slices.Contains(headers, header) {
+			headers = append(headers, header)
+		}
+	}
+	for _, key := range keys {
+		if !slices.Contains(oldGrid.AllHeaders(), key) || !slices.Contains(newGrid.AllHeaders(), key) {
+			return nil, DiffSummary{}, fmt.Errorf("Key column '%s' is not present in both results", key)
+		}
+	}
+
+	marker := DiffMarkerHeader
+	for i := 1; slices.Contains(headers, marker); i++ {
+		marker = fmt.Sprintf("%s_%d", DiffMarkerHeader, i)
+	}
+	dg := &DataGrid{
+		Headers:     append([]string{marker}, headers...),
+		diff:        make(map[uintptr]DiffStatus),
+		textMeasure: newGrid.textMeasure,
+	}
+	add := func(row map[string]any, status DiffStatus) {
+		row[marker] = diffMarker[status]
+		dg.Data = append(dg.Data, row)
+		dg.diff[rowIdentity(row)] = status
+	}
+
+	var summary DiffSummary
+	if len(keys) == 0 {
+		// Whole rows are compared as multisets, duplicated rows have to match the same number of times
+		oldRows := make(map[string][]map[string]any)
+		for _, row := range oldGrid.AllRows() {
+			signature := diffRowSignature(row, headers)
+			oldRows[signature] = append(oldRows[signature], row)
+		}
+		for _, row := range newGrid.AllRows() {
+			signature := diffRowSignature(row, headers)
+			if matches := oldRows[signature]; len(matches) > 0 {
+				oldRows[signature] = matches[1:]
+				summary.Unchanged++
+				continue
+			}
+			add(copyRowColumns(row, headers), DiffAdded)
+			summary.Added++
+		}
+		for _, row := range oldGrid.AllRows() {
+			signature := diffRowSignature(row, headers)
+			if matches := oldRows[signature]; len(matches) > 0 {
+				oldRows[signature] = matches[1:]
+				add(copyRowColumns(row, headers), DiffRemoved)
+				summary.Removed++
+			}
+		}
+	} else {
+		oldRows := make(map[string]map[string]any, len(oldGrid.AllRows()))
+		for _, row := range oldGrid.AllRows() {
+			key := diffRowSignature(row, keys)
+			if _, exists := oldRows[key]; exists {
+				return nil, DiffSummary{}, fmt.Errorf("Key %s is not unique in compared result", strings.Join(keys, ", "))
+			}
+			oldRows[key] = row
+		}
+		seen := make(map[string]bool, len(newGrid.AllRows()))
+		for _, row := range newGrid.AllRows() {
+			key := diffRowSignature(row, keys)
+			if seen[key] {
+				return nil, DiffSummary{}, fmt.Errorf("Key %s is not unique in current result", strings.Join(keys, ", "))
+			}
+			seen[key] = true
+
+			oldRow, ok := oldRows[key]
+			if !ok {
+				add(copyRowColumns(row, headers), DiffAdded)
+				summary.Added++
+				continue
+			}
+			diffRow := make(map[string]any, len(headers)+1)
+			var changed bool
+			for _, header := range headers {
+				diffRow[header] = row[header]
+				if !diffValuesEqual(oldRow[header], row[header]) {
+					diffRow[header] = CellChange{Old: oldRow[header], New: row[header]}
+					changed = true
+				}
+			}
+			if !changed {
+				summary.Unchanged++
+				continue
+			}
+			add(diffRow, DiffChanged)
+			summary.Changed++
+		}
+		for _, row := range oldGrid.AllRows() {
+			if !seen[diffRowSignature(row, keys)] {
+				add(copyRowColumns(row, headers), DiffRemoved)
+				summary.Removed++
+			}
+		}
+	}
+
+	dg.Rows = int32(len(dg.Data))
+	dg.Cols = int32(len(dg.Headers))
+	dg.computeColumnsWidth()
+	return dg, summary, nil
+}
+
+// copyRowColumns copies row restricted to given headers, so rows of diff do not share maps with compared results
+func copyRowColumns(row map[string]any, headers []string) map[string]any {
+	copied := make(map[string]any, len(headers)+1)
+	for _, header := range headers {
+		copied[header] = row[header]
+	}
+	return copied
+}
+
+func diffRowSignature(row map[string]any, headers []string) string {
+	var sb strings.Builder
+	for _, header := range headers {
+		val, ok := row[header]
+		switch {
+		case !ok:
+			sb.WriteString("\x01")
+		case val == nil:
+			sb.WriteString("\x02")
+		default:
+			sb.WriteString(format.GetValueAsString(val))
+		}
+		sb.WriteByte(0)
+	}
+	return sb.String()
+}
+
+func diffValuesEqual(a, b any) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return format.GetValueAsString(a) == format.GetValueAsString(b)
+}
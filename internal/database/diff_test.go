@@ -0,0 +1,112 @@
+package database
+
+import (
+	"slices"
+	"testing"
+)
+
+func newTestGrid(headers []string, rows ...[]any) *DataGrid {
+	dg := &DataGrid{Headers: headers, Rows: int32(len(rows)), Cols: int32(len(headers))}
+	for _, values := range rows {
+		row := make(map[string]any, len(headers))
+		for i, header := range headers {
+			row[header] = values[i]
+		}
+		dg.Data = append(dg.Data, row)
+	}
+	return dg
+}
+
+func TestDiffGridsByKey(t *testing.T) {
+	oldGrid := newTestGrid([]string{"id", "name"},
+		[]any{int64(1), "a"},
+		[]any{int64(2), "b"},
+		[]any{int64(3), nil},
+	)
+	newGrid := newTestGrid([]string{"id", "name"},
+		[]any{int64(1), "a"},
+		[]any{int64(3), "c"},
+		[]any{int64(4), "d"},
+	)
+
+	dg, summary, err := DiffGrids(oldGrid, newGrid, []string{"id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary != (DiffSummary{Added: 1, Removed: 1, Changed: 1, Unchanged: 1}) {
+		t.Errorf("summary %s, want +1 -1 ~1 (1 unchanged)", summary)
+	}
+	if want := []string{DiffMarkerHeader, "id", "name"}; !slices.Equal(dg.Headers, want) {
+		t.Errorf("headers %v, want %v", dg.Headers, want)
+	}
+
+	want := []struct {
+		marker string
+		status DiffStatus
+		id     int64
+		name   any
+	}{
+		{"~", DiffChanged, 3, CellChange{Old: nil, New: "c"}},
+		{"+", DiffAdded, 4, "d"},
+		{"-", DiffRemoved, 2, "b"},
+	}
+	if dg.Rows != int32(len(want)) {
+		t.Fatalf("diff has %d rows, want %d", dg.Rows, len(want))
+	}
+	for i, w := range want {
+		row := dg.Data[i]
+		if row[DiffMarkerHeader] != w.marker || dg.DiffStatus(int32(i)) != w.status || row["id"] != w.id || row["name"] != w.name {
+			t.Errorf("row %d is %v (status %d), want %+v", i, row, dg.DiffStatus(int32(i)), w)
+		}
+	}
+}
+
+func TestDiffGridsWithoutKey(t *testing.T) {
+	oldGrid := newTestGrid([]string{"name"}, []any{"a"}, []any{"a"}, []any{"b"})
+	newGrid := newTestGrid([]string{"name"}, []any{"a"}, []any{"b"}, []any{"b"})
+
+	dg, summary, err := DiffGrids(oldGrid, newGrid, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Duplicates are matched as many times as they occur
+	if summary != (DiffSummary{Added: 1, Removed: 1, Unchanged: 2}) {
+		t.Errorf("summary %s, want +1 -1 ~0 (2 unchanged)", summary)
+	}
+	if dg.Rows != 2 || dg.Data[0]["name"] != "b" || dg.DiffStatus(0) != DiffAdded || dg.Data[1]["name"] != "a" || dg.DiffStatus(1) != DiffRemoved {
+		t.Errorf("diff rows %v, want added b and removed a", dg.Data)
+	}
+}
+
+func TestDiffGridsMarkerDoesNotReplaceColumn(t *testing.T) {
+	oldGrid := newTestGrid([]string{"id", DiffMarkerHeader, DiffMarkerHeader + "_1"}, []any{int64(1), "x", "y"})
+	newGrid := newTestGrid([]string{"id", DiffMarkerHeader, DiffMarkerHeader + "_1"}, []any{int64(1), "z", "y"})
+
+	dg, _, err := DiffGrids(oldGrid, newGrid, []string{"id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	marker := DiffMarkerHeader + "_2"
+	if dg.Headers[0] != marker {
+		t.Fatalf("marker column is '%s', want '%s'", dg.Headers[0], marker)
+	}
+	if dg.Data[0][marker] != "~" || dg.Data[0][DiffMarkerHeader] != (CellChange{Old: "x", New: "z"}) {
+		t.Errorf("diff row %v keeps neither marker nor changed column", dg.Data[0])
+	}
+}
+
+func TestDiffGridsErrors(t *testing.T) {
+	unique := newTestGrid([]string{"id"}, []any{int64(1)}, []any{int64(2)})
+	duplicated := newTestGrid([]string{"id"}, []any{int64(1)}, []any{int64(1)})
+	other := newTestGrid([]string{"code"}, []any{"a"})
+
+	if _, _, err := DiffGrids(unique, other, []string{"id"}); err == nil {
+		t.Error("diff by key missing in current result succeeded")
+	}
+	if _, _, err := DiffGrids(duplicated, unique, []string{"id"}); err == nil {
+		t.Error("diff by key duplicated in compared result succeeded")
+	}
+	if _, _, err := DiffGrids(unique, duplicated, []string{"id"}); err == nil {
+		t.Error("diff by key duplicated in current result succeeded")
+	}
+}
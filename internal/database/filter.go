@@ -0,0 +1,432 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/quar15/qq-go/internal/format"
+)
+
+// RowFilter is compiled filter expression, e.g. `amount > 100 and status = 'paid'`.
+//
+// Supported syntax:
+//
+//	expr       := and_expr { "or" and_expr }
+//	and_expr   := not_expr { "and" not_expr }
+//	not_expr   := "not" not_expr | "(" expr ")" | comparison
+//	comparison := column ( op value | "is" ["not"] "null" | ["not"] ("like" | "ilike") string )
+//	op         := "=" | "!=" | "<>" | "<" | "<=" | ">" | ">="
+//
+// Columns are plain or double quoted names, values are single quoted strings, numbers, true, false or null.
+type RowFilter struct {
+	Expression string
+	match      func(row map[string]any) bool
+}
+
+func (f *RowFilter) Match(row map[string]any) bool {
+	return f.match(row)
+}
+
+type filterTokenKind int8
+
+const (
+	filterTokenIdent filterTokenKind = iota
+	filterTokenString
+	filterTokenNumber
+	filterTokenOperator
+	filterTokenOpenParen
+	filterTokenCloseParen
+	filterTokenEOF
+)
+
+type filterToken struct {
+	kind   filterTokenKind
+	text   string
+	quoted bool // double quoted identifier
+	pos    int
+}
+
+type filterParser struct {
+	tokens  []filterToken
+	pos     int
+	headers []string
+}
+
+// ParseRowFilter compiles filter expression against headers of the grid
+func ParseRowFilter(expression string, headers []string) (*RowFilter, error) {
+	tokens, err := tokenizeFilter(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens, headers: headers}
+	match, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != filterTokenEOF {
+		return nil, fmt.Errorf("Unexpected '%s' at %d", tok.text, tok.pos+1)
+	}
+	return &RowFilter{Expression: strings.TrimSpace(expression), match: match}, nil
+}
+
+func tokenizeFilter(expression string) ([]filterToken, error) {
+	var tokens []filterToken
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, filterToken{kind: filterTokenOpenParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, filterToken{kind: filterTokenCloseParen, text: ")", pos: i})
+			i++
+		case r == '\'' || r == '"':
+			text, next, err := readQuoted(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			kind := filterTokenString
+			if r == '"' {
+				kind = filterTokenIdent
+			}
+			tokens = append(tokens, filterToken{kind: kind, text: text, quoted: r == '"', pos: i})
+			i = next
+		case strings.ContainsRune("=!<>", r):
+			start := i
+			i++
+			if i < len(runes) && (runes[i] == '=' || (r == '<' && runes[i] == '>')) {
+				i++
+			}
+			op := string(runes[start:i])
+			if op == "!" {
+				return nil, fmt.Errorf("Unexpected '!' at %d", start+1)
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenOperator, text: op, pos: start})
+		case unicode.IsDigit(r) || ((r == '-' || r == '.') && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == 'e' || runes[i] == 'E') {
+				i++
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenNumber, text: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenIdent, text: string(runes[start:i]), pos: start})
+		default:
+			return nil, fmt.Errorf("Unexpected '%c' at %d", r, i+1)
+		}
+	}
+	return append(tokens, filterToken{kind: filterTokenEOF, pos: len(runes)}), nil
+}
+
+// readQuoted reads quoted text, doubled quote character is an escaped quote
+func readQuoted(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	var sb strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		if runes[i] != quote {
+			sb.WriteRune(runes[i])
+			continue
+		}
+		if i+1 < len(runes) && runes[i+1] == quote {
+			sb.WriteRune(quote)
+			i++
+			continue
+		}
+		return sb.String(), i + 1, nil
+	}
+	return "", 0, fmt.Errorf("Unterminated %c at %d", quote, start+1)
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() filterToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != filterTokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *filterParser) acceptKeyword(keyword string) bool {
+	tok := p.peek()
+	if tok.kind == filterTokenIdent && !tok.quoted && strings.EqualFold(tok.text, keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) parseOr() (func(map[string]any) bool, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(row map[string]any) bool { return l(row) || right(row) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (func(map[string]any) bool, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(row map[string]any) bool { return l(row) && right(row) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseNot() (func(map[string]any) bool, error) {
+	if p.acceptKeyword("not") {
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(row map[string]any) bool { return !inner(row) }, nil
+	}
+	if p.peek().kind == filterTokenOpenParen {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != filterTokenCloseParen {
+			return nil, fmt.Errorf("Expected ')' at %d", tok.pos+1)
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (func(map[string]any) bool, error) {
+	tok := p.next()
+	if tok.kind != filterTokenIdent {
+		return nil, fmt.Errorf("Expected column at %d", tok.pos+1)
+	}
+	header, err := p.resolveColumn(tok)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.acceptKeyword("is") {
+		negate := p.acceptKeyword("not")
+		if !p.acceptKeyword("null") {
+			return nil, fmt.Errorf("Expected NULL at %d", p.peek().pos+1)
+		}
+		return func(row map[string]any) bool { return (row[header] == nil) != negate }, nil
+	}
+
+	negate := p.acceptKeyword("not")
+	if p.acceptKeyword("like") {
+		return p.parseLike(header, false, negate)
+	}
+	if p.acceptKeyword("ilike") {
+		return p.parseLike(header, true, negate)
+	}
+	if negate {
+		return nil, fmt.Errorf("Expected LIKE at %d", p.peek().pos+1)
+	}
+
+	op := p.next()
+	if op.kind != filterTokenOperator {
+		return nil, fmt.Errorf("Expected operator after '%s' at %d", header, op.pos+1)
+	}
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, fmt.Errorf("Use IS NULL or IS NOT NULL to compare with NULL")
+	}
+
+	var accept func(c int) bool
+	switch op.text {
+	case "=":
+		accept = func(c int) bool { return c == 0 }
+	case "!=", "<>":
+		accept = func(c int) bool { return c != 0 }
+	case "<":
+		accept = func(c int) bool { return c < 0 }
+	case "<=":
+		accept = func(c int) bool { return c <= 0 }
+	case ">":
+		accept = func(c int) bool { return c > 0 }
+	case ">=":
+		accept = func(c int) bool { return c >= 0 }
+	default:
+		return nil, fmt.Errorf("Unknown operator '%s'", op.text)
+	}
+
+	return func(row map[string]any) bool {
+		cell := row[header]
+		// Like in SQL comparison with NULL is never true
+		if cell == nil {
+			return false
+		}
+		return accept(compareFilterValue(cell, value))
+	}, nil
+}
+
+func (p *filterParser) parseLike(header string, caseInsensitive bool, negate bool) (func(map[string]any) bool, error) {
+	tok := p.next()
+	if tok.kind != filterTokenString {
+		return nil, fmt.Errorf("Expected pattern at %d", tok.pos+1)
+	}
+	var sb strings.Builder
+	if caseInsensitive {
+		sb.WriteString("(?is)")
+	} else {
+		sb.WriteString("(?s)")
+	}
+	sb.WriteString("^")
+	for _, r := range tok.text {
+		switch r {
+		case '%':
+			sb.WriteString(".*")
+		case '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return nil, err
+	}
+	return func(row map[string]any) bool {
+		cell := row[header]
+		if cell == nil {
+			return false
+		}
+		return re.MatchString(format.GetValueAsString(cell)) != negate
+	}, nil
+}
+
+func (p *filterParser) parseValue() (any, error) {
+	tok := p.next()
+	switch tok.kind {
+	case filterTokenString:
+		return tok.text, nil
+	case filterTokenNumber:
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid number '%s' at %d", tok.text, tok.pos+1)
+		}
+		return f, nil
+	case filterTokenIdent:
+		if !tok.quoted {
+			switch strings.ToLower(tok.text) {
+			case "true":
+				return true, nil
+			case "false":
+				return false, nil
+			case "null":
+				return nil, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("Expected value at %d", tok.pos+1)
+}
+
+// resolveColumn matches column name exactly, unquoted names also case-insensitively
+func (p *filterParser) resolveColumn(tok filterToken) (string, error) {
+	for _, h := range p.headers {
+		if h == tok.text {
+			return h, nil
+		}
+	}
+	if !tok.quoted {
+		for _, h := range p.headers {
+			if strings.EqualFold(h, tok.text) {
+				return h, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("Unknown column '%s'", tok.text)
+}
+
+// compareFilterValue compares cell with literal of filter, text literal is parsed as time when cell is timestamp
+func compareFilterValue(cell any, value any) int {
+	switch c := cell.(type) {
+	case time.Time:
+		if s, ok := value.(string); ok {
+			if t, err := parseImportTime(s); err == nil {
+				return c.Compare(t)
+			}
+		}
+	case pgtype.Date:
+		if s, ok := value.(string); ok && c.InfinityModifier == pgtype.Finite {
+			if t, err := parseImportTime(s); err == nil {
+				return c.Time.Compare(t)
+			}
+		}
+	case pgtype.Timestamp:
+		if s, ok := value.(string); ok && c.InfinityModifier == pgtype.Finite {
+			if t, err := parseImportTime(s); err == nil {
+				return c.Time.Compare(t)
+			}
+		}
+	case bool:
+		if s, ok := value.(string); ok {
+			if b, err := parseImportBool(s); err == nil {
+				return CompareValues(c, b)
+			}
+		}
+	}
+	return CompareValues(cell, value)
+}
+
+// FilterLiteral renders value as literal of filter expression (used by quick filters)
+func FilterLiteral(val any) string {
+	switch v := val.(type) {
+	case nil:
+		return "null"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return format.GetValueAsString(v)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return "'" + strings.ReplaceAll(format.GetValueAsString(v), "'", "''") + "'"
+	}
+}
+
+// FilterColumn renders column name for filter expression, quoting it when needed
+func FilterColumn(header string) string {
+	for i, r := range header {
+		if !(unicode.IsLetter(r) || r == '_' || (i > 0 && unicode.IsDigit(r))) {
+			return `"` + strings.ReplaceAll(header, `"`, `""`) + `"`
+		}
+	}
+	switch strings.ToLower(header) {
+	case "and", "or", "not", "is", "null", "like", "ilike", "true", "false", "":
+		return `"` + header + `"`
+	}
+	return header
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestParseRowFilter(t *testing.T) {
+	headers := []string{"id", "status", "Amount", "created", "paid"}
+	rows := []map[string]any{
+		{"id": int64(1), "status": "paid", "Amount": 150.5, "created": time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC), "paid": true},
+		{"id": int64(2), "status": "open", "Amount": 20.0, "created": time.Date(2024, 2, 1, 8, 0, 0, 0, time.UTC), "paid": false},
+		{"id": int64(3), "status": nil, "Amount": nil, "created": time.Date(2023, 12, 31, 23, 0, 0, 0, time.UTC), "paid": false},
+		{"id": int64(4), "status": "Paid later", "Amount": 100.0, "created": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), "paid": nil},
+	}
+	tests := []struct {
+		expression string
+		want       []int64 // ids of matching rows
+	}{
+		{"id = 2", []int64{2}},
+		{"id != 2", []int64{1, 3, 4}},
+		{"id <> 2", []int64{1, 3, 4}},
+		{"amount > 100", []int64{1}},
+		{"amount >= 100", []int64{1, 4}},
+		{"amount < 100", []int64{2}},
+		{`"Amount" <= 20`, []int64{2}},
+		{"status = 'paid'", []int64{1}},
+		{"status is null", []int64{3}},
+		{"status is not null", []int64{1, 2, 4}},
+		{"status like 'p%'", []int64{1}},
+		{"status ilike 'p%'", []int64{1, 4}},
+		{"status not like '%a%'", []int64{2}},
+		{"status like '_pen'", []int64{2}},
+		{"paid = true", []int64{1}},
+		{"paid = 'no'", []int64{2, 3}},
+		{"created >= '2024-01-01' and created < '2024-02-01'", []int64{1}},
+		{"status = 'paid' or amount < 50", []int64{1, 2}},
+		{"not (status = 'paid' or amount < 50)", []int64{3, 4}},
+		{"status = 'open' or status = 'paid' and amount > 200", []int64{2}},
+		{"STATUS = 'it''s'", nil},
+	}
+	for _, tt := range tests {
+		filter, err := ParseRowFilter(tt.expression, headers)
+		if err != nil {
+			t.Errorf("ParseRowFilter(%q): %s", tt.expression, err)
+			continue
+		}
+		var got []int64
+		for _, row := range rows {
+			if filter.Match(row) {
+				got = append(got, row["id"].(int64))
+			}
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%q matched %v, want %v", tt.expression, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%q matched %v, want %v", tt.expression, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestParseRowFilterDates(t *testing.T) {
+	day := func(d int) pgtype.Date {
+		return pgtype.Date{Time: time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC), Valid: true}
+	}
+	filter, err := ParseRowFilter("day > '2024-01-02'", []string{"day"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter.Match(map[string]any{"day": day(2)}) || !filter.Match(map[string]any{"day": day(3)}) {
+		t.Error("date column is not compared chronologically with text literal")
+	}
+}
+
+func TestParseRowFilterErrors(t *testing.T) {
+	headers := []string{"id", "Name"}
+	for _, expression := range []string{
+		"",
+		"missing = 1",
+		`"name" = 'a'`,
+		"id = null",
+		"id =",
+		"id 1",
+		"(id = 1",
+		"id = 1)",
+		"id = 'unterminated",
+		"id ! 1",
+		"id not = 1",
+		"id like 1",
+		"id = 1 and",
+		"id = 1 # 2",
+	} {
+		if _, err := ParseRowFilter(expression, headers); err == nil {
+			t.Errorf("ParseRowFilter(%q) succeeded", expression)
+		}
+	}
+}
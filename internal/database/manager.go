@@ -12,6 +12,7 @@ type ConnectionManager struct {
 	connections map[string]*ConnectionData
 	factory     ConnectionFactory
 	current     *ConnectionData
+	listener    *NotificationListener
 	mu          sync.RWMutex
 }
 
@@ -138,7 +139,73 @@ func (mgr *ConnectionManager) ExecuteQuery(ctx context.Context, connectionKey st
 	return nil
 }
 
+// Listen subscribes to channels of current connection. Listener is (re)created when current connection changed.
+// Connecting and LISTEN run asynchronously, onDone is called for each channel once it finished or failed.
+func (mgr *ConnectionManager) Listen(channels []string, onDone func(channel string, err error)) error {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+
+	if mgr.current == nil {
+		return fmt.Errorf("No connection selected")
+	}
+
+	if mgr.listener != nil && (mgr.listener.ConnectionName != mgr.current.Name || !mgr.listener.IsAlive()) {
+		mgr.listener.Close()
+		mgr.listener = nil
+	}
+	if mgr.listener == nil {
+		listener, err := newNotificationListener(mgr.current)
+		if err != nil {
+			return err
+		}
+		mgr.listener = listener
+	}
+
+	for _, channel := range channels {
+		if err := mgr.listener.Listen(channel, onDone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Unlisten unsubscribes from channels. Without channels listener is closed completely
+func (mgr *ConnectionManager) Unlisten(channels []string) error {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+
+	if mgr.listener == nil {
+		return fmt.Errorf("Not listening on any channel")
+	}
+
+	if len(channels) == 0 {
+		mgr.listener.Close()
+		mgr.listener = nil
+		return nil
+	}
+
+	for _, channel := range channels {
+		if err := mgr.listener.Unlisten(channel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (mgr *ConnectionManager) GetNotificationListener() *NotificationListener {
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+	return mgr.listener
+}
+
 func (mgr *ConnectionManager) Close(ctx context.Context) {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+
+	if mgr.listener != nil {
+		mgr.listener.Close()
+		mgr.listener = nil
+	}
 	for _, connData := range mgr.connections {
 		if connData.Conn != nil {
 			connData.Conn.Close(ctx)
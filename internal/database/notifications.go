@@ -0,0 +1,233 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const maxStoredNotifications int = 500
+const notificationPollInterval time.Duration = 250 * time.Millisecond
+
+type Notification struct {
+	Timestamp time.Time
+	Channel   string
+	PID       uint32
+	Payload   string
+}
+
+type listenRequest struct {
+	channel string
+	listen  bool
+	onDone  func(channel string, err error) // called by listener goroutine once statement finished, can be nil
+}
+
+// NotificationListener keeps dedicated connection used only for LISTEN, so queries from editor are not blocked
+type NotificationListener struct {
+	ConnectionName string
+	mu             sync.RWMutex
+	channels       []string
+	notifications  []Notification
+	requests       chan listenRequest
+	cancel         context.CancelFunc
+	done           chan struct{}
+	stopped        bool // set once listener goroutine ends, requests are not accepted anymore
+	err            error
+}
+
+// newNotificationListener starts listener goroutine, connection is made by it so unreachable host does not block caller.
+// Requests made meanwhile wait for the connection.
+func newNotificationListener(connData *ConnectionData) (*NotificationListener, error) {
+	if connData.Driver != "postgresql" {
+		return nil, fmt.Errorf("LISTEN is not supported by driver: %s", connData.Driver)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &NotificationListener{
+		ConnectionName: connData.Name,
+		requests:       make(chan listenRequest, 16),
+		cancel:         cancel,
+		done:           make(chan struct{}),
+	}
+	go l.run(ctx, connData.ConnString)
+
+	slog.Debug("Started notification listener", slog.String("connection", connData.Name))
+	return l, nil
+}
+
+func (l *NotificationListener) run(ctx context.Context, connString string) {
+	defer close(l.done)
+	defer l.stop()
+
+	conn, err := pgx.Connect(ctx, connString)
+	if err != nil {
+		slog.Error("Unable to connect notification listener", slog.String("connection", l.ConnectionName), slog.Any("error", err))
+		l.setErr(err)
+		return
+	}
+	defer conn.Close(context.Background())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case req := <-l.requests:
+			err := l.applyRequest(ctx, conn, req)
+			if err != nil {
+				slog.Error("Failed to change listened channels", slog.String("channel", req.channel), slog.Any("error", err))
+				l.setErr(err)
+			}
+			if req.onDone != nil {
+				req.onDone(req.channel, err)
+			}
+			continue
+		default:
+		}
+
+		waitCtx, cancelWait := context.WithTimeout(ctx, notificationPollInterval)
+		n, err := conn.WaitForNotification(waitCtx)
+		cancelWait()
+		if err != nil {
+			if ctx.Err() != nil || pgconn.Timeout(err) || errors.Is(err, context.DeadlineExceeded) {
+				continue
+			}
+			slog.Error("Notification listener stopped", slog.Any("error", err))
+			l.setErr(err)
+			return
+		}
+		if n != nil {
+			l.push(Notification{
+				Timestamp: time.Now(),
+				Channel:   n.Channel,
+				PID:       n.PID,
+				Payload:   n.Payload,
+			})
+		}
+	}
+}
+
+func (l *NotificationListener) applyRequest(ctx context.Context, conn *pgx.Conn, req listenRequest) error {
+	statement := "UNLISTEN "
+	if req.listen {
+		statement = "LISTEN "
+	}
+	if _, err := conn.Exec(ctx, statement+pgx.Identifier{req.channel}.Sanitize()); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	idx := slices.Index(l.channels, req.channel)
+	if req.listen && idx < 0 {
+		l.channels = append(l.channels, req.channel)
+	} else if !req.listen && idx >= 0 {
+		l.channels = slices.Delete(l.channels, idx, idx+1)
+	}
+	slog.Debug("Listened channels changed", slog.Any("channels", l.channels))
+	return nil
+}
+
+func (l *NotificationListener) push(n Notification) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.notifications = append(l.notifications, n)
+	if len(l.notifications) > maxStoredNotifications {
+		l.notifications = l.notifications[len(l.notifications)-maxStoredNotifications:]
+	}
+}
+
+func (l *NotificationListener) setErr(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.err = err
+}
+
+// Listen requests LISTEN on channel, onDone reports its result from listener goroutine
+func (l *NotificationListener) Listen(channel string, onDone func(channel string, err error)) error {
+	return l.request(listenRequest{channel: channel, listen: true, onDone: onDone})
+}
+
+func (l *NotificationListener) Unlisten(channel string) error {
+	return l.request(listenRequest{channel: channel, listen: false})
+}
+
+func (l *NotificationListener) request(req listenRequest) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.stopped {
+		if l.err != nil {
+			return l.err
+		}
+		return errors.New("Notification listener is closed")
+	}
+	select {
+	case l.requests <- req:
+		return nil
+	default:
+		return errors.New("Too many pending LISTEN requests")
+	}
+}
+
+// stop rejects further requests and reports requests which were not run, e.g. when connection failed
+func (l *NotificationListener) stop() {
+	l.mu.Lock()
+	l.stopped = true
+	err := l.err
+	l.mu.Unlock()
+	if err == nil {
+		err = errors.New("Notification listener is closed")
+	}
+
+	for {
+		select {
+		case req := <-l.requests:
+			if req.onDone != nil {
+				req.onDone(req.channel, err)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (l *NotificationListener) IsAlive() bool {
+	select {
+	case <-l.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// Channels returns copy of currently listened channels
+func (l *NotificationListener) Channels() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return slices.Clone(l.channels)
+}
+
+// Notifications returns copy of received notifications, oldest first
+func (l *NotificationListener) Notifications() []Notification {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return slices.Clone(l.notifications)
+}
+
+func (l *NotificationListener) Err() error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.err
+}
+
+func (l *NotificationListener) Close() {
+	l.cancel()
+	<-l.done
+	slog.Debug("Closed notification listener", slog.String("connection", l.ConnectionName))
+}
@@ -0,0 +1,256 @@
+package database
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/quar15/qq-go/internal/format"
+)
+
+type PivotAggregate int8
+
+const (
+	PivotCount PivotAggregate = iota
+	PivotSum
+	PivotAvg
+	PivotMin
+	PivotMax
+)
+
+var pivotAggregateName = map[PivotAggregate]string{
+	PivotCount: "count",
+	PivotSum:   "sum",
+	PivotAvg:   "avg",
+	PivotMin:   "min",
+	PivotMax:   "max",
+}
+
+func (a PivotAggregate) String() string {
+	return pivotAggregateName[a]
+}
+
+func ParsePivotAggregate(name string) (PivotAggregate, bool) {
+	for aggregate, aggregateName := range pivotAggregateName {
+		if strings.EqualFold(name, aggregateName) {
+			return aggregate, true
+		}
+	}
+	return PivotCount, false
+}
+
+// PivotSpec groups rows by Rows fields, distinct values of Column (optional) become columns of result
+// and cells hold Aggregate of Value. Count without Value counts rows.
+type PivotSpec struct {
+	Rows      []string
+	Column    string
+	Value     string
+	Aggregate PivotAggregate
+}
+
+// Describe returns short description of pivot, e.g. "status x region: sum(amount)"
+func (s PivotSpec) Describe() string {
+	var sb strings.Builder
+	sb.WriteString(strings.Join(s.Rows, ", "))
+	if s.Column != "" {
+		sb.WriteString(" x " + s.Column)
+	}
+	sb.WriteString(": " + s.aggregateHeader())
+	return sb.String()
+}
+
+func (s PivotSpec) aggregateHeader() string {
+	if s.Value == "" {
+		return s.Aggregate.String()
+	}
+	return fmt.Sprintf("%s(%s)", s.Aggregate, s.Value)
+}
+
+// PivotGrid aggregates displayed rows of the grid into new result
+func PivotGrid(dg *DataGrid, spec PivotSpec) (*DataGrid, error) {
+	headers := dg.AllHeaders()
+	fields := slices.Clone(spec.Rows)
+	if spec.Column != "" {
+		fields = append(fields, spec.Column)
+	}
+	if spec.Value != "" {
+		fields = append(fields, spec.Value)
+	}
+	for _, field := range fields {
+		if !slices.Contains(headers, field) {
+			return nil, fmt.Errorf("Column '%s' is not in result", field)
+		}
+	}
+	if len(spec.Rows) == 0 {
+		return nil, fmt.Errorf("No row field to group by")
+	}
+	if spec.Value == "" && spec.Aggregate != PivotCount {
+		return nil, fmt.Errorf("Aggregate %s needs value column", spec.Aggregate)
+	}
+	if slices.Contains(spec.Rows, spec.Column) {
+		return nil, fmt.Errorf("Column '%s' cannot be both row and column field", spec.Column)
+	}
+
+	// Groups and column values keep first seen value, so their order follows values instead of their text
+	type pivotGroup struct {
+		keys   []any
+		values map[string][]any
+	}
+	groups := make(map[string]*pivotGroup)
+	columnValues := make(map[string]any)
+	for _, row := range dg.Data {
+		signature := diffRowSignature(row, spec.Rows)
+		group, ok := groups[signature]
+		if !ok {
+			group = &pivotGroup{values: make(map[string][]any)}
+			for _, field := range spec.Rows {
+				group.keys = append(group.keys, row[field])
+			}
+			groups[signature] = group
+		}
+		var column string
+		if spec.Column != "" {
+			column = diffRowSignature(row, []string{spec.Column})
+			if _, ok := columnValues[column]; !ok {
+				columnValues[column] = row[spec.Column]
+			}
+		}
+		var value any = true
+		if spec.Value != "" {
+			value = row[spec.Value]
+		}
+		group.values[column] = append(group.values[column], value)
+	}
+
+	sortedGroups := make([]*pivotGroup, 0, len(groups))
+	for _, group := range groups {
+		sortedGroups = append(sortedGroups, group)
+	}
+	keyComparers := make([]func(a, b any) int, len(spec.Rows))
+	for i := range spec.Rows {
+		keys := make([]any, len(sortedGroups))
+		for j, group := range sortedGroups {
+			keys[j] = group.keys[i]
+		}
+		keyComparers[i] = ValueComparer(keys)
+	}
+	slices.SortFunc(sortedGroups, func(a, b *pivotGroup) int {
+		for i := range a.keys {
+			if c := keyComparers[i](a.keys[i], b.keys[i]); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+
+	// Single aggregate column without column field, one column per distinct value otherwise
+	type pivotColumn struct {
+		header    string
+		signature string
+	}
+	var columns []pivotColumn
+	if spec.Column == "" {
+		columns = append(columns, pivotColumn{header: spec.aggregateHeader()})
+	} else {
+		signatures := make([]string, 0, len(columnValues))
+		for signature := range columnValues {
+			signatures = append(signatures, signature)
+		}
+		values := make([]any, len(signatures))
+		for i, signature := range signatures {
+			values[i] = columnValues[signature]
+		}
+		compare := ValueComparer(values)
+		slices.SortFunc(signatures, func(a, b string) int {
+			return compare(columnValues[a], columnValues[b])
+		})
+		for _, signature := range signatures {
+			header := format.GetDisplayValue(columnValues[signature])
+			if columnValues[signature] == nil {
+				header = "NULL"
+			}
+			if slices.Contains(spec.Rows, header) || slices.ContainsFunc(columns, func(c pivotColumn) bool { return c.header == header }) {
+				header = spec.Column + "=" + header
+			}
+			columns = append(columns, pivotColumn{header: header, signature: signature})
+		}
+	}
+
+	result := &DataGrid{
+		Headers:        slices.Clone(spec.Rows),
+		ConnectionName: dg.ConnectionName,
+		textMeasure:    dg.textMeasure,
+	}
+	for _, column := range columns {
+		result.Headers = append(result.Headers, column.header)
+	}
+	for _, group := range sortedGroups {
+		row := make(map[string]any, len(result.Headers))
+		for i, field := range spec.Rows {
+			row[field] = group.keys[i]
+		}
+		for _, column := range columns {
+			values, ok := group.values[column.signature]
+			if !ok {
+				row[column.header] = nil
+				continue
+			}
+			row[column.header] = aggregatePivotValues(values, spec.Aggregate, spec.Value == "")
+		}
+		result.Data = append(result.Data, row)
+	}
+
+	result.Rows = int32(len(result.Data))
+	result.Cols = int32(len(result.Headers))
+	result.computeColumnsWidth()
+	return result, nil
+}
+
+// aggregatePivotValues computes aggregate of single pivot cell, NULL values are ignored like in SQL
+func aggregatePivotValues(values []any, aggregate PivotAggregate, countRows bool) any {
+	if aggregate == PivotCount && countRows {
+		return int64(len(values))
+	}
+	agg := ComputeAggregates(values)
+	switch aggregate {
+	case PivotCount:
+		return int64(agg.NonNull)
+	case PivotSum:
+		return pivotNumber(agg.Sum)
+	case PivotAvg:
+		return pivotNumber(agg.Avg())
+	}
+
+	// Min and max work for any comparable values, e.g. dates and text
+	var extreme any
+	compare := ValueComparer(values)
+	for _, val := range values {
+		if val == nil {
+			continue
+		}
+		c := compare(val, extreme)
+		if extreme == nil || (aggregate == PivotMin && c < 0) || (aggregate == PivotMax && c > 0) {
+			extreme = val
+		}
+	}
+	return extreme
+}
+
+// pivotNumber converts aggregated number to value supported by formatting and export, nil stays NULL
+func pivotNumber(f *big.Float) any {
+	if f == nil {
+		return nil
+	}
+	if f.IsInt() {
+		if i, accuracy := f.Int64(); accuracy == big.Exact {
+			return i
+		}
+	}
+	v, _ := f.Float64()
+	if math.IsInf(v, 0) {
+		return f.Text('g', 20)
+	}
+	return v
+}
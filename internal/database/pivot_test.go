@@ -0,0 +1,92 @@
+package database
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func newSalesGrid() *DataGrid {
+	return newTestGrid([]string{"region", "status", "amount"},
+		[]any{"north", "paid", int64(10)},
+		[]any{"south", "open", int64(5)},
+		[]any{"north", "open", int64(7)},
+		[]any{"north", "paid", int64(3)},
+		[]any{nil, "paid", nil},
+	)
+}
+
+func TestPivotGrid(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        PivotSpec
+		wantHeaders []string
+		wantRows    [][]any
+	}{
+		{
+			name:        "count rows",
+			spec:        PivotSpec{Rows: []string{"region"}},
+			wantHeaders: []string{"region", "count"},
+			wantRows:    [][]any{{"north", int64(3)}, {"south", int64(1)}, {nil, int64(1)}},
+		},
+		{
+			name:        "count values",
+			spec:        PivotSpec{Rows: []string{"region"}, Value: "amount"},
+			wantHeaders: []string{"region", "count(amount)"},
+			wantRows:    [][]any{{"north", int64(3)}, {"south", int64(1)}, {nil, int64(0)}},
+		},
+		{
+			name:        "sum by column values",
+			spec:        PivotSpec{Rows: []string{"region"}, Column: "status", Value: "amount", Aggregate: PivotSum},
+			wantHeaders: []string{"region", "open", "paid"},
+			wantRows:    [][]any{{"north", int64(7), int64(13)}, {"south", int64(5), nil}, {nil, nil, nil}},
+		},
+		{
+			name:        "max",
+			spec:        PivotSpec{Rows: []string{"status"}, Value: "amount", Aggregate: PivotMax},
+			wantHeaders: []string{"status", "max(amount)"},
+			wantRows:    [][]any{{"open", int64(7)}, {"paid", int64(10)}},
+		},
+		{
+			name:        "avg",
+			spec:        PivotSpec{Rows: []string{"status"}, Value: "amount", Aggregate: PivotAvg},
+			wantHeaders: []string{"status", "avg(amount)"},
+			wantRows:    [][]any{{"open", int64(6)}, {"paid", 6.5}},
+		},
+	}
+	for _, tt := range tests {
+		dg, err := PivotGrid(newSalesGrid(), tt.spec)
+		if err != nil {
+			t.Errorf("%s: %s", tt.name, err)
+			continue
+		}
+		if !slices.Equal(dg.Headers, tt.wantHeaders) {
+			t.Errorf("%s: headers %v, want %v", tt.name, dg.Headers, tt.wantHeaders)
+			continue
+		}
+		var got [][]any
+		for _, row := range dg.Data {
+			values := make([]any, len(dg.Headers))
+			for i, header := range dg.Headers {
+				values[i] = row[header]
+			}
+			got = append(got, values)
+		}
+		if !reflect.DeepEqual(got, tt.wantRows) {
+			t.Errorf("%s: rows %v, want %v", tt.name, got, tt.wantRows)
+		}
+	}
+}
+
+func TestPivotGridErrors(t *testing.T) {
+	for _, spec := range []PivotSpec{
+		{},
+		{Rows: []string{"missing"}},
+		{Rows: []string{"region"}, Aggregate: PivotSum},
+		{Rows: []string{"region"}, Column: "region"},
+	} {
+		if _, err := PivotGrid(newSalesGrid(), spec); err == nil {
+			t.Errorf("PivotGrid(%+v) succeeded", spec)
+		}
+	}
+}
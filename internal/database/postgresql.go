@@ -2,10 +2,15 @@ package database
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"slices"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type queryResult struct {
@@ -26,12 +31,19 @@ func queryRows(ctx context.Context, conn *pgx.Conn, query string) (ch chan query
 		}
 		defer rows.Close()
 
-		fieldDescriptions := rows.FieldDescriptions()
+		// pgconn reuses buffer of descriptions for next query, detectTableSource queries after reading rows
+		fieldDescriptions := slices.Clone(rows.FieldDescriptions())
 		dg := &DataGrid{}
 		dg.Cols = 0
 		dg.Headers = make([]string, len(fieldDescriptions))
+		dg.ColumnTypes = make([]string, len(fieldDescriptions))
+		converters := make([]func(any) any, len(fieldDescriptions))
 		for i, field := range fieldDescriptions {
 			dg.Headers[i] = string(field.Name)
+			if t, ok := conn.TypeMap().TypeForOID(field.DataTypeOID); ok {
+				dg.ColumnTypes[i] = t.Name
+				converters[i] = columnConverter(t.Name)
+			}
 			dg.Cols++
 		}
 
@@ -50,6 +62,9 @@ func queryRows(ctx context.Context, conn *pgx.Conn, query string) (ch chan query
 
 			rowMap := make(map[string]any)
 			for i, col := range values {
+				if converters[i] != nil {
+					col = converters[i](col)
+				}
 				rowMap[dg.Headers[i]] = col
 			}
 
@@ -62,12 +77,91 @@ func queryRows(ctx context.Context, conn *pgx.Conn, query string) (ch chan query
 			return
 		default:
 		}
+		rows.Close()
+
+		source, err := detectTableSource(ctx, conn, fieldDescriptions)
+		if err != nil {
+			slog.Warn("Failed to detect source table of result", slog.Any("error", err))
+		} else if source != nil {
+			dg.Source = source
+			dg.Changes = NewChangeSet()
+		}
 		ch <- queryResult{dg, nil}
 	}()
 
 	return ch
 }
 
+// detectTableSource returns source table of result when every column comes from the same table with primary key
+func detectTableSource(ctx context.Context, conn *pgx.Conn, fields []pgconn.FieldDescription) (*TableSource, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	tableOID := fields[0].TableOID
+	for _, field := range fields {
+		if field.TableOID == 0 || field.TableOID != tableOID {
+			return nil, nil
+		}
+	}
+
+	const attributesQuery string = `
+		SELECT n.nspname, c.relname, a.attnum, a.attname, COALESCE(a.attnum = ANY(i.indkey), false)
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_index i ON i.indrelid = a.attrelid AND i.indisprimary
+		WHERE a.attrelid = $1 AND a.attnum > 0 AND NOT a.attisdropped`
+
+	rows, err := conn.Query(ctx, attributesQuery, tableOID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		schemaName    string
+		tableName     string
+		columnNames   = map[uint16]string{}
+		primaryKeyNum = map[uint16]bool{}
+	)
+	for rows.Next() {
+		var (
+			attNum       int16
+			attName      string
+			isPrimaryKey bool
+		)
+		if err := rows.Scan(&schemaName, &tableName, &attNum, &attName, &isPrimaryKey); err != nil {
+			return nil, err
+		}
+		columnNames[uint16(attNum)] = attName
+		if isPrimaryKey {
+			primaryKeyNum[uint16(attNum)] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(primaryKeyNum) == 0 {
+		return nil, nil
+	}
+
+	source := &TableSource{Schema: schemaName, Name: tableName, Columns: make(map[string]string, len(fields))}
+	for _, field := range fields {
+		source.Columns[field.Name] = columnNames[field.TableAttributeNumber]
+		if primaryKeyNum[field.TableAttributeNumber] {
+			source.PrimaryKey = append(source.PrimaryKey, field.Name)
+			delete(primaryKeyNum, field.TableAttributeNumber)
+		}
+	}
+	// Whole primary key is required to address rows
+	if len(primaryKeyNum) > 0 {
+		return nil, nil
+	}
+
+	slog.Debug("Detected source table of result", slog.String("table", source.String()), slog.Any("primaryKey", source.PrimaryKey))
+	return source, nil
+}
+
 type PostgresConn struct {
 	*pgx.Conn
 }
@@ -99,3 +193,79 @@ func connectToPostgres(connString string) (*pgx.Conn, error) {
 
 	return conn, nil
 }
+
+// columnConverter returns conversion of values of postgres type which pgx decodes like values of other types:
+// dates and timestamps without time zone like timestamptz, json arrays like postgres arrays
+func columnConverter(typeName string) func(any) any {
+	switch typeName {
+	case "date", "_date", "daterange", "_daterange", "datemultirange":
+		return func(val any) any { return convertTimes(val, asDate) }
+	case "timestamp", "_timestamp", "tsrange", "_tsrange", "tsmultirange":
+		return func(val any) any { return convertTimes(val, asTimestamp) }
+	case "json", "jsonb":
+		return asJSON
+	case "_json", "_jsonb":
+		return func(val any) any {
+			elements, ok := val.([]any)
+			if !ok {
+				return val
+			}
+			converted := make([]any, len(elements))
+			for i, element := range elements {
+				converted[i] = asJSON(element)
+			}
+			return converted
+		}
+	}
+	return nil
+}
+
+// convertTimes replaces decoded times (also in arrays and ranges) by value of their column type
+func convertTimes(val any, convert func(t time.Time, infinity pgtype.InfinityModifier) any) any {
+	switch v := val.(type) {
+	case time.Time:
+		return convert(v, pgtype.Finite)
+	case pgtype.InfinityModifier:
+		return convert(time.Time{}, v)
+	case []any:
+		converted := make([]any, len(v))
+		for i, element := range v {
+			converted[i] = convertTimes(element, convert)
+		}
+		return converted
+	case pgtype.Range[any]:
+		v.Lower, v.Upper = convertTimes(v.Lower, convert), convertTimes(v.Upper, convert)
+		return v
+	case pgtype.Multirange[pgtype.Range[any]]:
+		ranges := make(pgtype.Multirange[pgtype.Range[any]], len(v))
+		for i, r := range v {
+			ranges[i] = convertTimes(r, convert).(pgtype.Range[any])
+		}
+		return ranges
+	default:
+		return val
+	}
+}
+
+// asDate keeps date as pgtype.Date, so it is formatted without time
+func asDate(t time.Time, infinity pgtype.InfinityModifier) any {
+	return pgtype.Date{Time: t, InfinityModifier: infinity, Valid: true}
+}
+
+// asTimestamp keeps timestamp without time zone as pgtype.Timestamp, so it is formatted without offset and not
+// converted to display time zone
+func asTimestamp(t time.Time, infinity pgtype.InfinityModifier) any {
+	return pgtype.Timestamp{Time: t, InfinityModifier: infinity, Valid: true}
+}
+
+// asJSON keeps json value as its text, so json arrays are not formatted like postgres arrays
+func asJSON(val any) any {
+	if val == nil {
+		return nil
+	}
+	b, err := json.Marshal(val)
+	if err != nil {
+		return val
+	}
+	return json.RawMessage(b)
+}
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/quar15/qq-go/internal/format"
+)
+
+// CellMatch is position of cell matching search
+type CellMatch struct {
+	Row int32
+	Col int32
+}
+
+// SearchQuery matches displayed values of cells
+type SearchQuery struct {
+	Text       string
+	IgnoreCase bool
+	Regex      bool
+	Column     string // only cells of column are searched when set
+	lower      string
+	re         *regexp.Regexp
+}
+
+// ParseSearchQuery reads vim like pattern. `\c` makes search case insensitive, `\v` treats pattern as regular expression
+// and `\%c` limits search to given column, e.g. `\c\%cjohn`.
+func ParseSearchQuery(pattern string, column string) (SearchQuery, error) {
+	var q SearchQuery
+	for _, flag := range []string{`\c`, `\v`, `\%c`} {
+		if !strings.Contains(pattern, flag) {
+			continue
+		}
+		pattern = strings.ReplaceAll(pattern, flag, "")
+		switch flag {
+		case `\c`:
+			q.IgnoreCase = true
+		case `\v`:
+			q.Regex = true
+		case `\%c`:
+			q.Column = column
+		}
+	}
+	if pattern == "" {
+		return SearchQuery{}, fmt.Errorf("Empty search pattern")
+	}
+	q.Text = pattern
+
+	if q.Regex {
+		expr := pattern
+		if q.IgnoreCase {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return SearchQuery{}, fmt.Errorf("Invalid regular expression: %w", err)
+		}
+		q.re = re
+	} else if q.IgnoreCase {
+		q.lower = strings.ToLower(pattern)
+	}
+	return q, nil
+}
+
+func (q SearchQuery) Match(text string) bool {
+	switch {
+	case q.re != nil:
+		return q.re.MatchString(text)
+	case q.IgnoreCase:
+		return strings.Contains(strings.ToLower(text), q.lower)
+	default:
+		return strings.Contains(text, q.Text)
+	}
+}
+
+// Describe returns pattern together with its options, e.g. "john (ignore case, column name)"
+func (q SearchQuery) Describe() string {
+	var options []string
+	if q.Regex {
+		options = append(options, "regex")
+	}
+	if q.IgnoreCase {
+		options = append(options, "ignore case")
+	}
+	if q.Column != "" {
+		options = append(options, "column "+q.Column)
+	}
+	if len(options) == 0 {
+		return q.Text
+	}
+	return fmt.Sprintf("%s (%s)", q.Text, strings.Join(options, ", "))
+}
+
+// FindCells returns displayed cells matching query ordered by rows, then columns
+func (dg *DataGrid) FindCells(q SearchQuery) []CellMatch {
+	var matches []CellMatch
+	for row := int32(0); row < dg.Rows; row++ {
+		for col := int32(0); col < dg.Cols; col++ {
+			header := dg.Headers[col]
+			if q.Column != "" && header != q.Column {
+				continue
+			}
+			if q.Match(format.GetDisplayValue(dg.Data[row][header])) {
+				matches = append(matches, CellMatch{Row: row, Col: col})
+			}
+		}
+	}
+	return matches
+}
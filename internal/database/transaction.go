@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+type ExecResult struct {
+	Err             error
+	FailedStatement int // index of statement which failed, -1 when failure is not related to single statement
+}
+
+// ExecTransaction runs statements in single transaction on dedicated connection, so running queries are not affected
+func (mgr *ConnectionManager) ExecTransaction(connectionKey string, statements []Statement) (<-chan ExecResult, error) {
+	mgr.mu.RLock()
+	connData, ok := mgr.connections[connectionKey]
+	mgr.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("No connection '%s' found", connectionKey)
+	}
+	if connData.Driver != "postgresql" {
+		return nil, fmt.Errorf("Transactions are not supported by driver: %s", connData.Driver)
+	}
+	if len(statements) == 0 {
+		return nil, errors.New("Nothing to execute")
+	}
+
+	ch := make(chan ExecResult, 1)
+	go func() {
+		defer close(ch)
+		ctx := context.Background()
+
+		conn, err := connectToPostgres(connData.ConnString)
+		if err != nil {
+			ch <- ExecResult{Err: err, FailedStatement: -1}
+			return
+		}
+		defer conn.Close(ctx)
+
+		tx, err := conn.Begin(ctx)
+		if err != nil {
+			ch <- ExecResult{Err: err, FailedStatement: -1}
+			return
+		}
+		for i, statement := range statements {
+			slog.Debug("Executing statement in transaction", slog.String("sql", statement.SQL), slog.Any("args", statement.Args))
+			if _, err := tx.Exec(ctx, statement.SQL, statement.Args...); err != nil {
+				tx.Rollback(ctx)
+				ch <- ExecResult{Err: err, FailedStatement: i}
+				return
+			}
+		}
+		if err := tx.Commit(ctx); err != nil {
+			ch <- ExecResult{Err: err, FailedStatement: -1}
+			return
+		}
+		slog.Info("Transaction committed", slog.String("connection", connectionKey), slog.Int("statements", len(statements)))
+		ch <- ExecResult{FailedStatement: -1}
+	}()
+
+	return ch, nil
+}
@@ -0,0 +1,308 @@
+package database
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/quar15/qq-go/internal/format"
+)
+
+type SortKey struct {
+	Header     string
+	Descending bool
+}
+
+// AllRows returns every row of the result in original order, regardless of sorting and filtering
+func (dg *DataGrid) AllRows() []map[string]any {
+	if dg.allData != nil {
+		return dg.allData
+	}
+	return dg.Data
+}
+
+// TotalRows returns number of rows of the result in original order, regardless of sorting and filtering
+func (dg *DataGrid) TotalRows() int32 {
+	return int32(len(dg.AllRows()))
+}
+
+// CycleSort moves column through ascending, descending and original order.
+// Additive sort keeps other keys, so column becomes secondary (tertiary...) key.
+func (dg *DataGrid) CycleSort(header string, additive bool) {
+	idx := slices.IndexFunc(dg.SortKeys, func(key SortKey) bool { return key.Header == header })
+
+	var next *SortKey
+	switch {
+	case idx < 0:
+		next = &SortKey{Header: header}
+	case !dg.SortKeys[idx].Descending:
+		next = &SortKey{Header: header, Descending: true}
+	}
+
+	if !additive {
+		dg.SortKeys = nil
+		if next != nil {
+			dg.SortKeys = []SortKey{*next}
+		}
+	} else if idx < 0 {
+		dg.SortKeys = append(dg.SortKeys, *next)
+	} else if next != nil {
+		dg.SortKeys[idx] = *next
+	} else {
+		dg.SortKeys = slices.Delete(dg.SortKeys, idx, idx+1)
+	}
+
+	dg.RefreshView()
+}
+
+// SortDirection returns position of column among sort keys (1-based, 0 when not sorted) and its direction
+func (dg *DataGrid) SortDirection(header string) (int, bool) {
+	for i, key := range dg.SortKeys {
+		if key.Header == header {
+			return i + 1, key.Descending
+		}
+	}
+	return 0, false
+}
+
+// SortDescription describes sort keys for status messages, e.g. "name asc, id desc"
+func (dg *DataGrid) SortDescription() string {
+	parts := make([]string, len(dg.SortKeys))
+	for i, key := range dg.SortKeys {
+		direction := "asc"
+		if key.Descending {
+			direction = "desc"
+		}
+		parts[i] = fmt.Sprintf("%s %s", key.Header, direction)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SetFilter narrows displayed rows to the ones matching filter, nil clears the filter
+func (dg *DataGrid) SetFilter(filter *RowFilter) {
+	dg.Filter = filter
+	dg.RefreshView()
+}
+
+func (dg *DataGrid) IsFiltered() bool {
+	return dg.Filter != nil
+}
+
+// RefreshView rebuilds displayed rows from original ones using current filter and sort keys
+func (dg *DataGrid) RefreshView() {
+	if len(dg.SortKeys) == 0 && dg.Filter == nil {
+		if dg.allData != nil {
+			dg.Data = dg.allData
+			dg.allData = nil
+		}
+		dg.Rows = int32(len(dg.Data))
+		return
+	}
+
+	if dg.allData == nil {
+		dg.allData = dg.Data
+	}
+	if dg.Filter != nil {
+		dg.Data = make([]map[string]any, 0, len(dg.allData))
+		for _, row := range dg.allData {
+			if dg.Filter.Match(row) {
+				dg.Data = append(dg.Data, row)
+			}
+		}
+	} else {
+		dg.Data = slices.Clone(dg.allData)
+	}
+	comparers := make([]func(a, b any) int, len(dg.SortKeys))
+	for i, key := range dg.SortKeys {
+		values := make([]any, len(dg.Data))
+		for j, row := range dg.Data {
+			values[j] = row[key.Header]
+		}
+		comparers[i] = ValueComparer(values)
+	}
+	slices.SortStableFunc(dg.Data, func(a, b map[string]any) int {
+		for i, key := range dg.SortKeys {
+			c := comparers[i](a[key.Header], b[key.Header])
+			if c == 0 {
+				continue
+			}
+			// NULLs stay at the end in both directions
+			if key.Descending && a[key.Header] != nil && b[key.Header] != nil {
+				c = -c
+			}
+			return c
+		}
+		return 0
+	})
+	dg.Rows = int32(len(dg.Data))
+}
+
+// insertIntoAllRows keeps original rows in sync when row is added to sorted or filtered view
+func (dg *DataGrid) insertIntoAllRows(row map[string]any, after map[string]any) {
+	if dg.allData == nil {
+		return
+	}
+	idx := 0
+	if after != nil {
+		idx = slices.IndexFunc(dg.allData, func(r map[string]any) bool { return rowIdentity(r) == rowIdentity(after) }) + 1
+	}
+	dg.allData = slices.Insert(dg.allData, idx, row)
+}
+
+// removeFromAllRows keeps original rows in sync when rows are removed from sorted or filtered view
+func (dg *DataGrid) removeFromAllRows(remove func(row map[string]any) bool) {
+	if dg.allData == nil {
+		return
+	}
+	dg.allData = slices.DeleteFunc(dg.allData, remove)
+}
+
+// CompareValues orders values by their type: numbers numerically, timestamps chronologically, text lexically.
+// NULL is greater than any value. Text which looks like number (e.g. loaded from CSV) is compared as number, so it is
+// meant for single pair of values, ordering of column uses ValueComparer.
+func CompareValues(a any, b any) int {
+	return compareValues(a, b, true)
+}
+
+// ValueComparer returns comparison of values of single column like CompareValues. Text is compared as number only when
+// every text value of the column is number, mixing numeric and lexical comparison would not give consistent order.
+func ValueComparer(values []any) func(a, b any) int {
+	numericText := false
+	for _, val := range values {
+		text, ok := val.(string)
+		if !ok {
+			continue
+		}
+		if _, isNumber := numericValue(text); !isNumber {
+			numericText = false
+			break
+		}
+		numericText = true
+	}
+	return func(a, b any) int {
+		return compareValues(a, b, numericText)
+	}
+}
+
+func compareValues(a any, b any, numericText bool) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+
+	_, aIsText := a.(string)
+	_, bIsText := b.(string)
+	if numericText || (!aIsText && !bIsText) {
+		if x, ok := numericValue(a); ok {
+			if y, ok := numericValue(b); ok {
+				return x.Cmp(y)
+			}
+		}
+	}
+
+	switch x := a.(type) {
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y)
+		}
+	case pgtype.Date:
+		if y, ok := b.(pgtype.Date); ok {
+			if c := cmp.Compare(x.InfinityModifier, y.InfinityModifier); c != 0 {
+				return c
+			}
+			return x.Time.Compare(y.Time)
+		}
+	case pgtype.Timestamp:
+		if y, ok := b.(pgtype.Timestamp); ok {
+			if c := cmp.Compare(x.InfinityModifier, y.InfinityModifier); c != 0 {
+				return c
+			}
+			return x.Time.Compare(y.Time)
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0
+			case !x:
+				return -1
+			default:
+				return 1
+			}
+		}
+	case [16]uint8:
+		if y, ok := b.([16]uint8); ok {
+			return bytes.Compare(x[:], y[:])
+		}
+	case []byte:
+		if y, ok := b.([]byte); ok {
+			return bytes.Compare(x, y)
+		}
+	}
+
+	return cmp.Compare(format.GetValueAsString(a), format.GetValueAsString(b))
+}
+
+func numericValue(val any) (*big.Float, bool) {
+	switch v := val.(type) {
+	case int:
+		return new(big.Float).SetInt64(int64(v)), true
+	case int8:
+		return new(big.Float).SetInt64(int64(v)), true
+	case int16:
+		return new(big.Float).SetInt64(int64(v)), true
+	case int32:
+		return new(big.Float).SetInt64(int64(v)), true
+	case int64:
+		return new(big.Float).SetInt64(v), true
+	case uint8:
+		return new(big.Float).SetUint64(uint64(v)), true
+	case uint16:
+		return new(big.Float).SetUint64(uint64(v)), true
+	case uint32:
+		return new(big.Float).SetUint64(uint64(v)), true
+	case uint64:
+		return new(big.Float).SetUint64(v), true
+	case float32:
+		return finiteFloat(float64(v))
+	case float64:
+		return finiteFloat(v)
+	case pgtype.Numeric:
+		if !v.Valid || v.NaN || v.InfinityModifier != pgtype.Finite {
+			return nil, false
+		}
+		f := new(big.Float).SetInt(v.Int)
+		exp := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(v.Exp)).Abs(big.NewInt(int64(v.Exp))), nil))
+		if v.Exp > 0 {
+			f.Mul(f, exp)
+		} else if v.Exp < 0 {
+			f.Quo(f, exp)
+		}
+		return f, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, false
+		}
+		return finiteFloat(f)
+	}
+	return nil, false
+}
+
+func finiteFloat(f float64) (*big.Float, bool) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, false
+	}
+	return new(big.Float).SetFloat64(f), true
+}
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestCompareValues(t *testing.T) {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		a, b any
+		want int
+	}{
+		{"both NULL", nil, nil, 0},
+		{"NULL is greater", nil, int64(1), 1},
+		{"value is less than NULL", "a", nil, -1},
+		{"integers", int32(2), int64(10), -1},
+		{"integer and float", int64(3), 2.5, 1},
+		{"numeric", pgtype.Numeric{Int: big.NewInt(125), Exp: -2, Valid: true}, 1.2, 1},
+		{"numeric text", "2", "10", -1},
+		{"text", "b", "a", 1},
+		{"timestamps", day, day.Add(time.Hour), -1},
+		{"dates", pgtype.Date{Time: day, Valid: true}, pgtype.Date{Time: day.AddDate(0, 0, -1), Valid: true}, 1},
+		{"infinite date", pgtype.Date{InfinityModifier: pgtype.Infinity, Valid: true}, pgtype.Date{Time: day, Valid: true}, 1},
+		{"timestamps without time zone", pgtype.Timestamp{Time: day, Valid: true}, pgtype.Timestamp{Time: day, Valid: true}, 0},
+		{"booleans", false, true, -1},
+	}
+	for _, tt := range tests {
+		if got := CompareValues(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: CompareValues(%v, %v) = %d, want %d", tt.name, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestValueComparer(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []any
+		want   []any
+	}{
+		{"numeric text", []any{"10", "2", nil, "1.5"}, []any{"1.5", "2", "10", nil}},
+		{"mixed text is lexical", []any{"2", "10", "1a"}, []any{"10", "1a", "2"}},
+		{"numbers", []any{int64(10), nil, int64(2)}, []any{int64(2), int64(10), nil}},
+	}
+	for _, tt := range tests {
+		got := slices.Clone(tt.values)
+		slices.SortStableFunc(got, ValueComparer(tt.values))
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: sorted %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCycleSort(t *testing.T) {
+	dg := &DataGrid{
+		Data: []map[string]any{
+			{"name": "b", "amount": "10"},
+			{"name": "a", "amount": "9"},
+			{"name": "c", "amount": nil},
+			{"name": "a", "amount": "11"},
+		},
+		Headers: []string{"name", "amount"},
+		Rows:    4,
+		Cols:    2,
+	}
+	column := func(header string) []any {
+		values := make([]any, len(dg.Data))
+		for i, row := range dg.Data {
+			values[i] = row[header]
+		}
+		return values
+	}
+
+	dg.CycleSort("amount", false)
+	if got, want := column("amount"), []any{"9", "10", "11", nil}; !slices.Equal(got, want) {
+		t.Errorf("ascending %v, want %v", got, want)
+	}
+	dg.CycleSort("amount", false)
+	if got, want := column("amount"), []any{"11", "10", "9", nil}; !slices.Equal(got, want) {
+		t.Errorf("descending %v, want %v (NULL last)", got, want)
+	}
+	dg.CycleSort("name", false)
+	dg.CycleSort("amount", true)
+	if got, want := column("amount"), []any{"9", "11", "10", nil}; !slices.Equal(got, want) {
+		t.Errorf("by name and amount %v, want %v", got, want)
+	}
+	if got, want := dg.SortDescription(), "name asc, amount asc"; got != want {
+		t.Errorf("SortDescription() = %q, want %q", got, want)
+	}
+	dg.CycleSort("name", false)
+	dg.CycleSort("name", false)
+	if got, want := column("amount"), []any{"10", "9", nil, "11"}; !slices.Equal(got, want) || len(dg.SortKeys) != 0 {
+		t.Errorf("original order %v, want %v", got, want)
+	}
+}
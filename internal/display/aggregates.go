@@ -0,0 +1,92 @@
+package display
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/quar15/qq-go/internal/cursor"
+	"github.com/quar15/qq-go/internal/database"
+)
+
+// selectionAggregatesKey identifies selected cells, grid of other result tab is the same DataGrid with other rows
+type selectionAggregatesKey struct {
+	data             uintptr
+	rows             int32
+	headers          string // visible columns in their order, moving or hiding column changes selected cells
+	mode             cursor.Mode
+	startRow, endRow int32
+	startCol, endCol int32
+	changesRevision  int
+	filter           string
+	sort             string
+}
+
+// Aggregates are cached, so big selections are not summed again on every frame
+var (
+	lastSelectionAggregatesKey  selectionAggregatesKey
+	lastSelectionAggregatesText string
+)
+
+// selectionAggregatesText summarizes selected spreadsheet cells for status line, e.g. "Count: 3 | Sum: 6"
+func selectionAggregatesText(c *cursor.Cursor, dg *database.DataGrid) string {
+	switch c.Common.Mode {
+	case cursor.ModeVisual, cursor.ModeVLine, cursor.ModeVBlock:
+	default:
+		return ""
+	}
+
+	p := c.Position
+	key := selectionAggregatesKey{
+		data: reflect.ValueOf(dg.Data).Pointer(), rows: dg.Rows, mode: c.Common.Mode,
+		headers:  strings.Join(dg.Headers, "\x00"),
+		startRow: p.SelectStartRow, endRow: min(p.SelectEndRow, dg.Rows-1),
+		startCol: p.SelectStartCol, endCol: min(p.SelectEndCol, dg.Cols-1),
+		sort: dg.SortDescription(),
+	}
+	if dg.Changes != nil {
+		key.changesRevision = dg.Changes.Revision()
+	}
+	if dg.IsFiltered() {
+		key.filter = dg.Filter.Expression
+	}
+	if key == lastSelectionAggregatesKey {
+		return lastSelectionAggregatesText
+	}
+
+	var values []any
+	for row := key.startRow; row <= key.endRow; row++ {
+		for col := int32(0); col < dg.Cols; col++ {
+			if c.IsSelected(col, row) {
+				values = append(values, dg.Data[row][dg.Headers[col]])
+			}
+		}
+	}
+
+	agg := database.ComputeAggregates(values)
+	parts := []string{
+		fmt.Sprintf("Count: %d", agg.Count),
+		fmt.Sprintf("Non-null: %d", agg.NonNull),
+		fmt.Sprintf("Distinct: %d", agg.Distinct),
+	}
+	if agg.Numeric > 0 {
+		parts = append(parts,
+			fmt.Sprintf("Sum: %s", formatAggregate(agg.Sum)),
+			fmt.Sprintf("Avg: %s", formatAggregate(agg.Avg())),
+			fmt.Sprintf("Min: %s", formatAggregate(agg.Min)),
+			fmt.Sprintf("Max: %s", formatAggregate(agg.Max)),
+		)
+	}
+
+	lastSelectionAggregatesKey = key
+	lastSelectionAggregatesText = strings.Join(parts, " | ")
+	return lastSelectionAggregatesText
+}
+
+func formatAggregate(f *big.Float) string {
+	if f.IsInt() {
+		return f.Text('f', 0)
+	}
+	return strings.TrimRight(strings.TrimRight(f.Text('f', 6), "0"), ".")
+}
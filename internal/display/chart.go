@@ -0,0 +1,274 @@
+package display
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/quar15/qq-go/internal/assets"
+	"github.com/quar15/qq-go/internal/config"
+	"github.com/quar15/qq-go/internal/database"
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+// chartSeriesColors are used by series in order, colors repeat when there are more series
+func chartSeriesColors() []rl.Color {
+	colors := config.Get().Colors
+	return []rl.Color{colors.Accent(), colors.Green(), colors.Peach(), colors.Mauve(), colors.Yellow(), colors.Red(), colors.Blue()}
+}
+
+// DrawChart draws chart of current result, hovering plot shows values of the nearest point
+func (z *Zone) DrawChart(appAssets *assets.Assets, chart *mode.ChartView, dg *database.DataGrid) {
+	const headerHeight int32 = 24
+	const textPadding float32 = 6
+	const tickLength float32 = 4
+	const yTicks int = 5
+
+	rl.DrawRectangleRec(z.Bounds, config.Get().Colors.Mantle())
+	rl.DrawLineEx(
+		rl.Vector2{X: z.Bounds.X, Y: z.Bounds.Y},
+		rl.Vector2{X: z.Bounds.X, Y: z.Bounds.Y + z.Bounds.Height},
+		2,
+		config.Get().Colors.Crust(),
+	)
+	rl.BeginScissorMode(int32(z.Bounds.X), int32(z.Bounds.Y), int32(z.Bounds.Width), int32(z.Bounds.Height))
+	defer rl.EndScissorMode()
+
+	// Header with title and legend
+	var maxNumberOfCharacters int = int((z.Bounds.Width - textPadding*2) / appAssets.MainFontCharacterWidth)
+	rl.DrawRectangle(int32(z.Bounds.X), int32(z.Bounds.Y), int32(z.Bounds.Width), headerHeight, config.Get().Colors.Surface0())
+	data, err := chart.Data(dg)
+	if err != nil {
+		appAssets.DrawTextMainFont(
+			truncateText(fmt.Sprintf("Chart failed: %s", err), maxNumberOfCharacters),
+			rl.Vector2{X: z.Bounds.X + textPadding, Y: z.Bounds.Y + textPadding/2},
+			config.Get().Colors.Peach(),
+		)
+		return
+	}
+	var title string = fmt.Sprintf("%s by %s", strings.ToUpper(chart.Spec.Kind.String()), chart.Spec.XColumn)
+	if data.Sampled {
+		title += " (sampled)"
+	}
+	appAssets.DrawTextMainFont(truncateText(title, maxNumberOfCharacters), rl.Vector2{X: z.Bounds.X + textPadding, Y: z.Bounds.Y + textPadding/2}, config.Get().Colors.Text())
+
+	palette := chartSeriesColors()
+	var legendX float32 = z.Bounds.X + textPadding*3 + float32(len(title))*appAssets.MainFontCharacterWidth
+	for i, series := range data.Series {
+		var swatch float32 = appAssets.MainFontSize / 2
+		rl.DrawRectangleRec(rl.Rectangle{X: legendX, Y: z.Bounds.Y + (float32(headerHeight)-swatch)/2, Width: swatch, Height: swatch}, palette[i%len(palette)])
+		legendX += swatch + textPadding
+		appAssets.DrawTextMainFont(series.Name, rl.Vector2{X: legendX, Y: z.Bounds.Y + textPadding/2}, config.Get().Colors.Overlay1())
+		legendX += float32(len(series.Name))*appAssets.MainFontCharacterWidth + textPadding*2
+	}
+
+	// Plot area leaves space for labels of ticks
+	yTickValues, yStep := chartTicks(data.MinY, data.MaxY, yTicks)
+	var yLabelCharacters int
+	for _, v := range yTickValues {
+		yLabelCharacters = max(yLabelCharacters, len(formatChartNumber(v, yStep)))
+	}
+	plot := rl.Rectangle{
+		X: z.Bounds.X + textPadding*2 + float32(yLabelCharacters)*appAssets.MainFontCharacterWidth + tickLength,
+		Y: z.Bounds.Y + float32(headerHeight) + appAssets.MainFontSize,
+	}
+	plot.Width = z.Bounds.X + z.Bounds.Width - textPadding*3 - plot.X
+	plot.Height = z.Bounds.Y + z.Bounds.Height - appAssets.MainFontSize - textPadding*3 - plot.Y
+	if plot.Width < 20 || plot.Height < 20 || len(data.Labels) == 0 {
+		return
+	}
+
+	minY, maxY := yTickValues[0], yTickValues[len(yTickValues)-1]
+	toY := func(v float64) float32 {
+		return plot.Y + plot.Height - float32((v-minY)/(maxY-minY))*plot.Height
+	}
+	// Categories take equal slots, numeric X is scaled between its extremes
+	var slot float32 = plot.Width / float32(len(data.Labels))
+	toX := func(i int) float32 {
+		if data.XNumeric {
+			if data.MaxX == data.MinX {
+				return plot.X + plot.Width/2
+			}
+			return plot.X + float32((data.X[i]-data.MinX)/(data.MaxX-data.MinX))*plot.Width
+		}
+		return plot.X + slot*(float32(i)+0.5)
+	}
+
+	// Grid and Y axis labels
+	for _, v := range yTickValues {
+		var y float32 = toY(v)
+		rl.DrawLineEx(rl.Vector2{X: plot.X, Y: y}, rl.Vector2{X: plot.X + plot.Width, Y: y}, 1, config.Get().Colors.Surface0())
+		rl.DrawLineEx(rl.Vector2{X: plot.X - tickLength, Y: y}, rl.Vector2{X: plot.X, Y: y}, 1, config.Get().Colors.Overlay0())
+		var label string = formatChartNumber(v, yStep)
+		appAssets.DrawTextMainFont(label, rl.Vector2{
+			X: plot.X - tickLength - textPadding/2 - float32(len(label))*appAssets.MainFontCharacterWidth,
+			Y: y - appAssets.MainFontSize/2,
+		}, config.Get().Colors.Overlay1())
+	}
+	rl.DrawLineEx(rl.Vector2{X: plot.X, Y: plot.Y}, rl.Vector2{X: plot.X, Y: plot.Y + plot.Height}, 1, config.Get().Colors.Overlay0())
+	rl.DrawLineEx(rl.Vector2{X: plot.X, Y: plot.Y + plot.Height}, rl.Vector2{X: plot.X + plot.Width, Y: plot.Y + plot.Height}, 1, config.Get().Colors.Overlay0())
+
+	// X axis labels, every n-th label is drawn so they do not overlap
+	var labelY float32 = plot.Y + plot.Height + tickLength + textPadding/2
+	if data.XNumeric {
+		xTickValues, xStep := chartTicks(data.MinX, data.MaxX, max(2, int(plot.Width/(appAssets.MainFontCharacterWidth*12))))
+		for _, v := range xTickValues {
+			if v < data.MinX || v > data.MaxX || data.MaxX == data.MinX {
+				continue
+			}
+			var x float32 = plot.X + float32((v-data.MinX)/(data.MaxX-data.MinX))*plot.Width
+			var label string = formatChartNumber(v, xStep)
+			rl.DrawLineEx(rl.Vector2{X: x, Y: plot.Y + plot.Height}, rl.Vector2{X: x, Y: plot.Y + plot.Height + tickLength}, 1, config.Get().Colors.Overlay0())
+			appAssets.DrawTextMainFont(label, rl.Vector2{X: x - float32(len(label))*appAssets.MainFontCharacterWidth/2, Y: labelY}, config.Get().Colors.Overlay1())
+		}
+	} else {
+		const maxLabelCharacters int = 12
+		var labelWidth float32 = float32(maxLabelCharacters+1) * appAssets.MainFontCharacterWidth
+		var every int = max(1, int(math.Ceil(float64(labelWidth/slot))))
+		for i := 0; i < len(data.Labels); i += every {
+			var x float32 = toX(i)
+			var label string = truncateText(data.Labels[i], maxLabelCharacters)
+			rl.DrawLineEx(rl.Vector2{X: x, Y: plot.Y + plot.Height}, rl.Vector2{X: x, Y: plot.Y + plot.Height + tickLength}, 1, config.Get().Colors.Overlay0())
+			appAssets.DrawTextMainFont(label, rl.Vector2{X: x - float32(len(label))*appAssets.MainFontCharacterWidth/2, Y: labelY}, config.Get().Colors.Overlay1())
+		}
+	}
+
+	// Series
+	rl.BeginScissorMode(int32(plot.X), int32(plot.Y)-1, int32(plot.Width)+1, int32(plot.Height)+2)
+	for s, series := range data.Series {
+		color := palette[s%len(palette)]
+		switch chart.Spec.Kind {
+		case database.ChartBar:
+			var groupWidth float32 = slot * 0.8
+			var barWidth float32 = max(1, groupWidth/float32(len(data.Series)))
+			var zeroY float32 = toY(math.Max(minY, 0))
+			for i, v := range series.Values {
+				if !series.Valid[i] {
+					continue
+				}
+				var x float32 = toX(i) - groupWidth/2 + float32(s)*barWidth
+				var y float32 = toY(v)
+				rl.DrawRectangleRec(rl.Rectangle{X: x, Y: min(y, zeroY), Width: max(1, barWidth-1), Height: max(1, float32(math.Abs(float64(zeroY-y))))}, color)
+			}
+		case database.ChartLine:
+			var prev rl.Vector2
+			var hasPrev bool
+			for i, v := range series.Values {
+				if !series.Valid[i] {
+					hasPrev = false
+					continue
+				}
+				point := rl.Vector2{X: toX(i), Y: toY(v)}
+				if hasPrev {
+					rl.DrawLineEx(prev, point, 2, color)
+				}
+				if slot >= 8 {
+					rl.DrawCircleV(point, 3, color)
+				}
+				prev, hasPrev = point, true
+			}
+		case database.ChartScatter:
+			for i, v := range series.Values {
+				if series.Valid[i] {
+					rl.DrawCircleV(rl.Vector2{X: toX(i), Y: toY(v)}, 3, color)
+				}
+			}
+		}
+	}
+	rl.EndScissorMode()
+
+	// Tooltip of point nearest to mouse
+	var mouse rl.Vector2 = rl.GetMousePosition()
+	if !rl.CheckCollisionPointRec(mouse, plot) {
+		return
+	}
+	var nearest int = -1
+	var nearestDistance float32 = float32(math.Inf(1))
+	for i := range data.Labels {
+		var distance float32 = float32(math.Abs(float64(toX(i) - mouse.X)))
+		if distance < nearestDistance {
+			nearest, nearestDistance = i, distance
+		}
+	}
+	if nearest < 0 {
+		return
+	}
+	var guideX float32 = toX(nearest)
+	rl.DrawLineEx(rl.Vector2{X: guideX, Y: plot.Y}, rl.Vector2{X: guideX, Y: plot.Y + plot.Height}, 1, config.Get().Colors.Overlay1())
+
+	lines := []string{fmt.Sprintf("%s: %s", chart.Spec.XColumn, data.Labels[nearest])}
+	lineColors := []rl.Color{config.Get().Colors.Text()}
+	for s, series := range data.Series {
+		var value string = "NULL"
+		if series.Valid[nearest] {
+			value = strconv.FormatFloat(series.Values[nearest], 'f', -1, 64)
+			rl.DrawCircleV(rl.Vector2{X: guideX, Y: toY(series.Values[nearest])}, 4, palette[s%len(palette)])
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", series.Name, value))
+		lineColors = append(lineColors, palette[s%len(palette)])
+	}
+	var tooltipCharacters int
+	for _, line := range lines {
+		tooltipCharacters = max(tooltipCharacters, len(line))
+	}
+	tooltip := rl.Rectangle{
+		X:      mouse.X + textPadding*2,
+		Y:      mouse.Y + textPadding*2,
+		Width:  float32(tooltipCharacters)*appAssets.MainFontCharacterWidth + textPadding*2,
+		Height: float32(len(lines))*appAssets.MainFontSize + textPadding*2,
+	}
+	// Tooltip stays inside the zone
+	if tooltip.X+tooltip.Width > z.Bounds.X+z.Bounds.Width {
+		tooltip.X = mouse.X - textPadding*2 - tooltip.Width
+	}
+	if tooltip.Y+tooltip.Height > z.Bounds.Y+z.Bounds.Height {
+		tooltip.Y = mouse.Y - textPadding*2 - tooltip.Height
+	}
+	rl.DrawRectangleRec(tooltip, config.Get().Colors.Surface0())
+	rl.DrawRectangleLinesEx(tooltip, 1, config.Get().Colors.Accent())
+	for i, line := range lines {
+		appAssets.DrawTextMainFont(line, rl.Vector2{
+			X: tooltip.X + textPadding,
+			Y: tooltip.Y + textPadding + float32(i)*appAssets.MainFontSize,
+		}, lineColors[i])
+	}
+}
+
+// chartTicks returns evenly spaced round values covering range, e.g. 0, 25, 50, 75, 100
+func chartTicks(minValue, maxValue float64, count int) ([]float64, float64) {
+	if minValue == maxValue {
+		minValue, maxValue = minValue-1, maxValue+1
+	}
+	var rawStep float64 = (maxValue - minValue) / float64(max(1, count))
+	var magnitude float64 = math.Pow(10, math.Floor(math.Log10(rawStep)))
+	var step float64 = magnitude * 10
+	for _, factor := range []float64{1, 2, 2.5, 5, 10} {
+		if rawStep <= factor*magnitude {
+			step = factor * magnitude
+			break
+		}
+	}
+	// Last tick is at or above maxValue, otherwise highest values would be drawn outside of axis
+	var ticks []float64
+	for v := math.Floor(minValue/step) * step; v < maxValue+step; v += step {
+		ticks = append(ticks, v)
+		if v >= maxValue {
+			break
+		}
+	}
+	return ticks, step
+}
+
+// formatChartNumber prints tick value with decimals needed by step of ticks
+func formatChartNumber(v float64, step float64) string {
+	var decimals int
+	if stepText := strconv.FormatFloat(step, 'f', -1, 64); strings.Contains(stepText, ".") {
+		decimals = len(stepText) - strings.Index(stepText, ".") - 1
+	}
+	if math.Abs(v) >= 1e9 {
+		return strconv.FormatFloat(v, 'g', 4, 64)
+	}
+	return strconv.FormatFloat(v, 'f', decimals, 64)
+}
@@ -7,9 +7,11 @@ import (
 	"github.com/quar15/qq-go/internal/assets"
 	"github.com/quar15/qq-go/internal/config"
 	"github.com/quar15/qq-go/internal/cursor"
+	"github.com/quar15/qq-go/internal/database"
+	"github.com/quar15/qq-go/internal/mode"
 )
 
-func (z *Zone) DrawCommandZone(cfg *config.Config, appAssets *assets.Assets, c *cursor.Cursor, currConnName string) {
+func (z *Zone) DrawCommandZone(cfg *config.Config, appAssets *assets.Assets, c *cursor.Cursor, dg *database.DataGrid, search *mode.Search, currConnName string) {
 	const textSpacing float32 = 4
 	var statusLineColor rl.Color = c.Common.Mode.Color()
 	// Status Line
@@ -19,6 +21,13 @@ func (z *Zone) DrawCommandZone(cfg *config.Config, appAssets *assets.Assets, c *
 	rl.DrawRectangle(int32(z.Bounds.X), int32(z.Bounds.Y), int32(modeStatusTextWidth+textSpacing*4), int32(z.Bounds.Height/2), statusLineColor)
 	// @TODO: Add horizontal spacing
 	appAssets.DrawTextMainFont(modeStatusText, rl.Vector2{X: z.Bounds.X + textSpacing*2, Y: z.Bounds.Y + textSpacing/2}, cfg.Colors.Mantle())
+	if c.Type == cursor.TypeSpreadsheet {
+		appAssets.DrawTextMainFont(
+			selectionAggregatesText(c, dg),
+			rl.Vector2{X: z.Bounds.X + modeStatusTextWidth + textSpacing*6, Y: z.Bounds.Y + textSpacing/2},
+			cfg.Colors.Text(),
+		)
+	}
 	var cursorPercentage int8 = 0
 	switch c.Type {
 	case cursor.TypeSpreadsheet:
@@ -35,6 +44,12 @@ func (z *Zone) DrawCommandZone(cfg *config.Config, appAssets *assets.Assets, c *
 		c.Position.Row+1, c.Position.MaxRow+1,
 		cursorPercentage,
 	)
+	if c.Type == cursor.TypeSpreadsheet && dg.IsFiltered() {
+		detailsStatusText = fmt.Sprintf("%d of %d rows | %s", dg.Rows, dg.TotalRows(), detailsStatusText)
+	}
+	if c.Type == cursor.TypeSpreadsheet && search.IsActive() {
+		detailsStatusText = fmt.Sprintf("%s | %s", search.Status(dg, c.Position), detailsStatusText)
+	}
 	var detailsStatusTextWidth float32 = appAssets.MeasureTextMainFont(detailsStatusText).X
 	var detailsStatusWidth float32 = detailsStatusTextWidth + textSpacing*4
 	rl.DrawRectangle(int32(z.Bounds.Width-detailsStatusWidth), int32(z.Bounds.Y), int32(detailsStatusWidth), int32(z.Bounds.Height/2), statusLineColor)
@@ -62,7 +77,11 @@ func (z *Zone) DrawCommandZone(cfg *config.Config, appAssets *assets.Assets, c *
 	// Command Input
 	rl.DrawRectangle(int32(z.Bounds.X), int32(z.Bounds.Y+z.Bounds.Height/2), int32(z.Bounds.Width), int32(z.Bounds.Height/2), cfg.Colors.Background())
 	c.Common.Logs.CheckForMessage()
-	appAssets.DrawTextMainFont(c.Common.Logs.LastMessage, rl.Vector2{X: z.Bounds.X + textSpacing, Y: z.Bounds.Y + z.Bounds.Height/2 + textSpacing/2}, cfg.Colors.Text())
+	var commandLineText string = c.Common.Logs.LastMessage
+	if c.Common.Mode == cursor.ModeCommand {
+		commandLineText = string(c.Common.CmdPrefix) + c.Common.CmdBuf
+	}
+	appAssets.DrawTextMainFont(commandLineText, rl.Vector2{X: z.Bounds.X + textSpacing, Y: z.Bounds.Y + z.Bounds.Height/2 + textSpacing/2}, cfg.Colors.Text())
 
 	var motionBufWidth float32 = appAssets.MeasureTextMainFont(c.Common.MotionBuf).X + textSpacing*8
 	appAssets.DrawTextMainFont(c.Common.MotionBuf, rl.Vector2{X: z.Bounds.Width - z.Bounds.X - motionBufWidth, Y: z.Bounds.Y + z.Bounds.Height/2 + textSpacing/2}, config.Get().Colors.Text())
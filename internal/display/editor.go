@@ -181,6 +181,7 @@ func updateEditorScrollBasedOnCursor(z *Zone, cursor *cursor.Cursor, renderParam
 	scrollRow = min(max(scrollRow, 0), cursor.Position.MaxRow)
 	z.ClampScrollsToZoneSize()
 	lastRowToRender = min(cursor.Position.MaxRow, scrollRow+int32(renderParams.RowsToRender))
+	updateCursorViewRows(cursor, scrollRow, renderParams.RowsToRender, renderParams.LinesPadding)
 
 	return scrollRow, lastRowToRender
 }
@@ -0,0 +1,69 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/quar15/qq-go/internal/assets"
+	"github.com/quar15/qq-go/internal/config"
+	"github.com/quar15/qq-go/internal/database"
+)
+
+func (z *Zone) DrawNotificationsPanel(appAssets *assets.Assets, listener *database.NotificationListener) {
+	const cellHeight int32 = 24
+	const textPadding int32 = 6
+	const timestampLayout string = "15:04:05.000"
+
+	rl.DrawRectangleRec(z.Bounds, config.Get().Colors.Mantle())
+	rl.DrawLineEx(
+		rl.Vector2{X: z.Bounds.X, Y: z.Bounds.Y},
+		rl.Vector2{X: z.Bounds.X, Y: z.Bounds.Y + z.Bounds.Height},
+		2,
+		config.Get().Colors.Crust(),
+	)
+
+	// Header
+	var headerText string = fmt.Sprintf("LISTEN %s @ %s", strings.Join(listener.Channels(), ", "), listener.ConnectionName)
+	var headerColor rl.Color = config.Get().Colors.Accent()
+	if err := listener.Err(); err != nil {
+		headerText = fmt.Sprintf("LISTEN failed: %s", err)
+		headerColor = config.Get().Colors.Peach()
+	}
+	rl.DrawRectangle(int32(z.Bounds.X), int32(z.Bounds.Y), int32(z.Bounds.Width), cellHeight, config.Get().Colors.Surface0())
+	var maxNumberOfCharacters int = int((int32(z.Bounds.Width) - textPadding*2) / int32(appAssets.MainFontCharacterWidth))
+	appAssets.DrawTextMainFont(
+		truncateText(headerText, maxNumberOfCharacters),
+		rl.Vector2{X: z.Bounds.X + float32(textPadding), Y: z.Bounds.Y + float32(textPadding)/2},
+		headerColor,
+	)
+
+	// Newest notifications are rendered first
+	notifications := listener.Notifications()
+	var visibleRows int = int((int32(z.Bounds.Height) - cellHeight) / cellHeight)
+	rl.BeginScissorMode(int32(z.Bounds.X), int32(z.Bounds.Y)+cellHeight, int32(z.Bounds.Width), int32(z.Bounds.Height)-cellHeight)
+	for i := 0; i < min(visibleRows, len(notifications)); i++ {
+		n := notifications[len(notifications)-1-i]
+		var cellY float32 = z.Bounds.Y + float32(cellHeight*int32(i+1)) + float32(textPadding)/2
+		var cellX float32 = z.Bounds.X + float32(textPadding)
+
+		var prefix string = fmt.Sprintf("%s %s (%d) ", n.Timestamp.Format(timestampLayout), n.Channel, n.PID)
+		appAssets.DrawTextMainFont(truncateText(prefix, maxNumberOfCharacters), rl.Vector2{X: cellX, Y: cellY}, config.Get().Colors.Overlay0())
+		if len(prefix) < maxNumberOfCharacters {
+			var payloadX float32 = cellX + float32(len(prefix))*appAssets.MainFontCharacterWidth
+			appAssets.DrawTextMainFont(truncateText(n.Payload, maxNumberOfCharacters-len(prefix)), rl.Vector2{X: payloadX, Y: cellY}, config.Get().Colors.Text())
+		}
+	}
+	rl.EndScissorMode()
+}
+
+func truncateText(text string, maxNumberOfCharacters int) string {
+	text = strings.ReplaceAll(text, "\n", " ")
+	if maxNumberOfCharacters <= 0 {
+		return ""
+	}
+	if len(text) > maxNumberOfCharacters {
+		return text[:maxNumberOfCharacters]
+	}
+	return text
+}
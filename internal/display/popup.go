@@ -0,0 +1,68 @@
+package display
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/quar15/qq-go/internal/assets"
+	"github.com/quar15/qq-go/internal/config"
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+func DrawPopup(appAssets *assets.Assets, popup *mode.Popup, screenWidth int32, screenHeight int32) {
+	if !popup.IsVisible() {
+		return
+	}
+	const widthRatio float32 = 0.8
+	const heightRatio float32 = 0.7
+	const textPadding float32 = 8
+	const boxRoundness float32 = 0.02
+	var lineHeight float32 = appAssets.MainFontSize + 4
+
+	box := rl.Rectangle{
+		Width:  float32(screenWidth) * widthRatio,
+		Height: float32(screenHeight) * heightRatio,
+	}
+	box.X = (float32(screenWidth) - box.Width) / 2
+	box.Y = (float32(screenHeight) - box.Height) / 2
+
+	rl.DrawRectangleRounded(box, boxRoundness, 0, config.Get().Colors.Mantle())
+	rl.DrawRectangleRoundedLinesEx(box, boxRoundness, 0, 2, config.Get().Colors.Accent())
+
+	var maxNumberOfCharacters int = int((box.Width - textPadding*2) / appAssets.MainFontCharacterWidth)
+	appAssets.DrawTextMainFont(
+		truncateText(popup.Title, maxNumberOfCharacters),
+		rl.Vector2{X: box.X + textPadding, Y: box.Y + textPadding},
+		config.Get().Colors.Accent(),
+	)
+	if popup.Footer != "" {
+		appAssets.DrawTextMainFont(
+			truncateText(popup.Footer, maxNumberOfCharacters),
+			rl.Vector2{X: box.X + textPadding, Y: box.Y + box.Height - textPadding - lineHeight},
+			config.Get().Colors.Overlay0(),
+		)
+	}
+
+	content := rl.Rectangle{
+		X:      box.X + textPadding,
+		Y:      box.Y + textPadding*2 + lineHeight,
+		Width:  box.Width - textPadding*2,
+		Height: box.Height - textPadding*4 - lineHeight*2,
+	}
+	popup.Layout(int(content.Width / appAssets.MainFontCharacterWidth))
+	var visibleLines int32 = int32(content.Height / lineHeight)
+	rl.BeginScissorMode(int32(content.X), int32(content.Y), int32(content.Width), int32(content.Height))
+	for i := popup.Scroll; i < min(popup.Scroll+visibleLines, int32(len(popup.Lines))); i++ {
+		var x float32 = content.X
+		var y float32 = content.Y + float32(i-popup.Scroll)*lineHeight
+		for _, segment := range popup.Lines[i] {
+			appAssets.DrawTextMainFont(segment.Text, rl.Vector2{X: x, Y: y}, segment.Color)
+			x += float32(len(segment.Text)) * appAssets.MainFontCharacterWidth
+		}
+	}
+	rl.EndScissorMode()
+
+	if int32(len(popup.Lines)) > visibleLines {
+		var thumbHeight float32 = content.Height * float32(visibleLines) / float32(len(popup.Lines))
+		var thumbY float32 = content.Y + (content.Height-thumbHeight)*float32(popup.Scroll)/float32(max(int32(len(popup.Lines))-1, 1))
+		rl.DrawRectangleRec(rl.Rectangle{X: box.X + box.Width - textPadding, Y: thumbY, Width: 4, Height: thumbHeight}, config.Get().Colors.Overlay0())
+	}
+}
@@ -0,0 +1,130 @@
+package display
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/quar15/qq-go/internal/assets"
+	"github.com/quar15/qq-go/internal/config"
+	"github.com/quar15/qq-go/internal/cursor"
+	"github.com/quar15/qq-go/internal/database"
+	"github.com/quar15/qq-go/internal/format"
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+// DrawRecordView draws current row as lines of field name, value and type, field of cursor column is highlighted
+func (z *Zone) DrawRecordView(appAssets *assets.Assets, record *mode.RecordView, search *mode.Search, dg *database.DataGrid, cursor *cursor.Cursor) {
+	const cellHeight int32 = 30
+	const textPadding int32 = 6
+	const maxNameCharacters int = 32
+	const maxTypeCharacters int = 20
+	var mouse rl.Vector2 = rl.GetMousePosition()
+
+	rl.DrawRectangleRec(z.Bounds, config.Get().Colors.Background())
+	var row int32 = cursor.Position.Row
+	fields := record.Fields(dg, row)
+	search.Matches(dg) // refresh matches before fields look them up
+
+	// Columns of names and types are as wide as their longest text
+	var nameCharacters, typeCharacters int = len("column"), 0
+	for _, col := range fields {
+		nameCharacters = max(nameCharacters, min(len(dg.Headers[col]), maxNameCharacters))
+		if int(col) < len(dg.ColumnTypes) {
+			typeCharacters = max(typeCharacters, min(len(dg.ColumnTypes[col]), maxTypeCharacters))
+		}
+	}
+	var nameWidth int32 = int32(float32(nameCharacters)*appAssets.MainFontCharacterWidth) + textPadding*2
+	var typeWidth int32 = int32(float32(typeCharacters)*appAssets.MainFontCharacterWidth) + textPadding*2
+	if typeCharacters == 0 {
+		typeWidth = 0
+	}
+	var valueWidth int32 = max(int32(z.Bounds.Width)-nameWidth-typeWidth, 0)
+
+	// Keep highlighted field visible
+	var visibleFields int32 = max(int32(z.Bounds.Height)/cellHeight-1, 1)
+	var focused int32 = -1
+	for i, col := range fields {
+		if col == cursor.Position.Col {
+			focused = int32(i)
+		}
+	}
+	var firstField int32 = int32(z.Scroll.Y) / cellHeight
+	if focused >= 0 {
+		if focused < firstField {
+			firstField = focused
+		}
+		if focused >= firstField+visibleFields {
+			firstField = focused - visibleFields + 1
+		}
+	}
+	firstField = max(min(firstField, int32(len(fields))-visibleFields), 0)
+	z.Scroll.Y = float32(firstField * cellHeight)
+
+	rl.BeginScissorMode(int32(z.Bounds.X), int32(z.Bounds.Y), int32(z.Bounds.Width), int32(z.Bounds.Height))
+	for i := firstField; i < min(int32(len(fields)), firstField+visibleFields+1); i++ {
+		col := fields[i]
+		header := dg.Headers[col]
+		var cellY int32 = int32(z.Bounds.Y) + (i-firstField+1)*cellHeight
+		var cellX int32 = int32(z.Bounds.X)
+		line := rl.Rectangle{X: float32(cellX), Y: float32(cellY), Width: z.Bounds.Width, Height: float32(cellHeight)}
+		if rl.IsMouseButtonPressed(rl.MouseButtonLeft) && rl.CheckCollisionPointRec(mouse, line) {
+			cursor.Position.Col = col
+		}
+
+		var bg rl.Color = config.Get().Colors.Background()
+		var border rl.Color = config.Get().Colors.Mantle()
+		if col == cursor.Position.Col {
+			bg = config.Get().Colors.Mantle()
+			border = config.Get().Colors.Accent()
+		}
+		if _, isMatch := search.MatchIndex(row, col); isMatch {
+			bg = config.Get().Colors.Surface1()
+		}
+		rl.DrawRectangleRec(line, bg)
+		rl.DrawRectangle(cellX, cellY, nameWidth, cellHeight, config.Get().Colors.Surface0())
+
+		var nameColor rl.Color = config.Get().Colors.Text()
+		if dg.IsPrimaryKeyColumn(header) {
+			nameColor = config.Get().Colors.Accent()
+		}
+		var textY float32 = float32(cellY + textPadding)
+		appAssets.DrawTextMainFont(truncateText(header, maxNameCharacters), rl.Vector2{X: float32(cellX + textPadding), Y: textY}, nameColor)
+
+		val := dg.Data[row][header]
+		var valueText string = format.GetDisplayValue(val)
+		var valueColor rl.Color = config.Get().Colors.Text()
+		switch {
+		case cursor.IsEditingCell(col, row):
+			valueText = cursor.Common.EditBuf
+		case val == nil:
+			valueColor = config.Get().Colors.Overlay0()
+		case dg.IsCellEdited(row, col):
+			valueColor = config.Get().Colors.Peach()
+		}
+		var maxValueCharacters int = int(float32(valueWidth-textPadding*2) / appAssets.MainFontCharacterWidth)
+		appAssets.DrawTextMainFont(truncateText(valueText, maxValueCharacters), rl.Vector2{X: float32(cellX + nameWidth + textPadding), Y: textY}, valueColor)
+
+		if typeWidth > 0 && int(col) < len(dg.ColumnTypes) {
+			appAssets.DrawTextMainFont(
+				truncateText(dg.ColumnTypes[col], maxTypeCharacters),
+				rl.Vector2{X: float32(cellX + nameWidth + valueWidth + textPadding), Y: textY},
+				config.Get().Colors.Overlay0(),
+			)
+		}
+		rl.DrawRectangleLinesEx(line, 2, border)
+	}
+	rl.EndScissorMode()
+
+	// Header describes row and search
+	var header string = fmt.Sprintf("Record %d/%d", min(row+1, dg.Rows), dg.Rows)
+	if record.Search != "" {
+		header += fmt.Sprintf(" | %d/%d field(s) match '%s'", len(fields), dg.Cols, record.Search)
+	}
+	rl.DrawRectangle(int32(z.Bounds.X), int32(z.Bounds.Y), int32(z.Bounds.Width), cellHeight, config.Get().Colors.Surface0())
+	var maxNumberOfCharacters int = int(float32(int32(z.Bounds.Width)-textPadding*2) / appAssets.MainFontCharacterWidth)
+	appAssets.DrawTextMainFont(
+		truncateText(header, maxNumberOfCharacters),
+		rl.Vector2{X: z.Bounds.X + float32(textPadding), Y: z.Bounds.Y + float32(textPadding)},
+		config.Get().Colors.Accent(),
+	)
+}
@@ -0,0 +1,64 @@
+package display
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/quar15/qq-go/internal/assets"
+	"github.com/quar15/qq-go/internal/config"
+	"github.com/quar15/qq-go/internal/cursor"
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+const ResultTabsHeight float32 = 26
+
+// DrawResultTabs draws tab strip of results, clicking tab switches to it and middle click closes it
+func (z *Zone) DrawResultTabs(appAssets *assets.Assets, results *mode.ResultTabs, logs *cursor.CommandLogs) {
+	const textPadding float32 = 8
+	const maxTabCharacters int = 48
+	var mouse rl.Vector2 = rl.GetMousePosition()
+
+	rl.DrawRectangleRec(z.Bounds, config.Get().Colors.Crust())
+	rl.BeginScissorMode(int32(z.Bounds.X), int32(z.Bounds.Y), int32(z.Bounds.Width), int32(z.Bounds.Height))
+	defer rl.EndScissorMode()
+
+	var tabX float32 = z.Bounds.X
+	for i, tab := range results.Tabs {
+		var title string = truncateText(fmt.Sprintf("%d: %s", i+1, tab.Title()), maxTabCharacters)
+		tabRect := rl.Rectangle{
+			X:      tabX,
+			Y:      z.Bounds.Y,
+			Width:  float32(len(title))*appAssets.MainFontCharacterWidth + textPadding*2,
+			Height: z.Bounds.Height,
+		}
+		tabX += tabRect.Width + 2
+
+		var bg rl.Color = config.Get().Colors.Mantle()
+		var textColor rl.Color = config.Get().Colors.Overlay1()
+		if i == results.Current {
+			bg = config.Get().Colors.Surface0()
+			textColor = config.Get().Colors.Text()
+		}
+		if tab.Pinned {
+			textColor = config.Get().Colors.Accent()
+		}
+		if rl.CheckCollisionPointRec(mouse, tabRect) {
+			bg = config.Get().Colors.Surface1()
+			if rl.IsMouseButtonPressed(rl.MouseButtonLeft) {
+				if _, err := results.Switch(i); err != nil {
+					logs.Log(err.Error())
+				}
+			} else if rl.IsMouseButtonPressed(rl.MouseButtonMiddle) {
+				if err := results.Close(i); err != nil {
+					logs.Log(err.Error())
+				}
+				return
+			}
+		}
+		rl.DrawRectangleRec(tabRect, bg)
+		if i == results.Current {
+			rl.DrawRectangleRec(rl.Rectangle{X: tabRect.X, Y: tabRect.Y + tabRect.Height - 2, Width: tabRect.Width, Height: 2}, config.Get().Colors.Accent())
+		}
+		appAssets.DrawTextMainFont(title, rl.Vector2{X: tabRect.X + textPadding, Y: tabRect.Y + (tabRect.Height-appAssets.MainFontSize)/2}, textColor)
+	}
+}
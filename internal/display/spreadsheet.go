@@ -1,6 +1,9 @@
 package display
 
 import (
+	"fmt"
+	"log/slog"
+	"slices"
 	"strconv"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
@@ -9,98 +12,181 @@ import (
 	"github.com/quar15/qq-go/internal/cursor"
 	"github.com/quar15/qq-go/internal/database"
 	"github.com/quar15/qq-go/internal/format"
+	"github.com/quar15/qq-go/internal/mode"
 )
 
-func (z *Zone) DrawSpreadsheetZone(appAssets *assets.Assets, dg *database.DataGrid, cursor *cursor.Cursor) {
-	// @TODO: Consider approach with RenderTexture2D for performance
+func (z *Zone) DrawSpreadsheetZone(appAssets *assets.Assets, dg *database.DataGrid, cursor *cursor.Cursor, search *mode.Search) {
 	const cellHeight int = 30
 	const textPadding int32 = 6
 	var mouse rl.Vector2 = rl.GetMousePosition()
+	var offsets []int32 = spreadsheetColumnOffsets(dg)
 	var (
 		counterColumnCharactersCount int = format.CountDigits(int(dg.Rows))
 		counterColumnWidth           int = int(appAssets.MainFontCharacterWidth)*counterColumnCharactersCount + int(textPadding*2)
-		contentWidth                 int = counterColumnWidth
+		contentWidth                 int = counterColumnWidth + int(offsets[dg.Cols])
 		contentHeight                int = cellHeight * (int(dg.Rows) + 2)
 	)
-	for col := int32(0); col < dg.Cols; col++ {
-		contentWidth += int(dg.ColumnsWidth[col])
-	}
 
 	const linesPadding int8 = 2
-	scrollRow, lastRowToRender := updateSpreadsheetScrollBasedOnCursor(z, dg, cursor, cellHeight, linesPadding)
-
-	rl.BeginScissorMode(int32(z.Bounds.X), int32(z.Bounds.Y), int32(z.Bounds.Width), int32(z.Bounds.Height))
+	scrollRow, lastRowToRender := updateSpreadsheetScrollBasedOnCursor(z, dg, cursor, offsets, cellHeight, linesPadding)
+	layout := newSpreadsheetLayout(z, dg, offsets, int32(counterColumnWidth))
+	handleSpreadsheetCellClick(z, dg, cursor, &layout, cellHeight, mouse)
+	search.Matches(dg) // refresh matches before cells look them up
 
-	for row := scrollRow; row < lastRowToRender; row++ {
-		renderContentRow(z, appAssets, dg, cursor, counterColumnWidth, cellHeight, textPadding, mouse, row)
-	}
+	z.drawCachedContentRows(appAssets, dg, cursor, search, &layout, cellHeight, textPadding, scrollRow, lastRowToRender)
 
+	rl.BeginScissorMode(int32(z.Bounds.X), int32(z.Bounds.Y), int32(z.Bounds.Width), int32(z.Bounds.Height))
 	for row := scrollRow; row < lastRowToRender; row++ {
 		renderSpreadsheetCounterColumnRow(z, appAssets, counterColumnWidth, counterColumnCharactersCount, cellHeight, textPadding, mouse, row)
 	}
-
 	rl.EndScissorMode()
 
 	// Draw static header
 	rl.DrawRectangle(int32(z.Bounds.X), int32(z.Bounds.Y), int32(z.Bounds.Width), int32(cellHeight), config.Get().Colors.Surface0()) // Left upper corner fill
-	renderSpreadsheetHeadersRow(z, appAssets, dg, counterColumnWidth, cellHeight, textPadding, mouse)
+	handleSpreadsheetColumnResize(z, dg, &layout, cellHeight, mouse)
+	renderSpreadsheetHeadersRow(z, appAssets, dg, &layout, cellHeight, textPadding, mouse)
+	if dg.FrozenCols > 0 {
+		var frozenEdgeX float32 = float32(layout.frozenEdgeX)
+		rl.DrawLineEx(rl.Vector2{X: frozenEdgeX, Y: z.Bounds.Y}, rl.Vector2{X: frozenEdgeX, Y: z.Bounds.Y + z.Bounds.Height}, 2, config.Get().Colors.Accent())
+	}
+	if dg.IsFiltered() {
+		renderSpreadsheetFilterBar(z, appAssets, dg, cellHeight, textPadding)
+	}
 
 	z.ContentSize.Y = max(float32(contentHeight), z.Bounds.Height)
 	z.ContentSize.X = max(float32(contentWidth), z.Bounds.Width)
 	z.drawScrollbars()
 }
 
-func renderContentRow(z *Zone, appAssets *assets.Assets, dg *database.DataGrid, cursor *cursor.Cursor, counterColumnWidth int, cellHeight int, textPadding int32, mouse rl.Vector2, row int32) {
-	for col, key := range dg.Headers {
-		// @TODO: Consider limiting draw to only visible columns
-		val := dg.Data[row][key]
-		var cellX int32 = int32(z.Bounds.X-z.Scroll.X) + int32(counterColumnWidth)
-		for i := range col {
-			cellX += dg.ColumnsWidth[i]
-		}
-		var cellY int32 = int32(z.Bounds.Y) + (row+1)*int32(cellHeight) - int32(z.Scroll.Y)
-
-		var cellBackgroundColor rl.Color = config.Get().Colors.Background()
-		var cellBorderColor rl.Color = config.Get().Colors.Mantle()
-		if cursor.IsFocused(int32(col), row) {
-			cellBackgroundColor = config.Get().Colors.Mantle()
-			cellBorderColor = config.Get().Colors.Accent()
-		}
-		if cursor.IsActive() && cursor.IsSelected(int32(col), row) {
-			cellBackgroundColor = config.Get().Colors.Surface1()
-		}
-		var cellRect rl.RectangleInt32 = rl.RectangleInt32{X: cellX, Y: cellY, Width: dg.ColumnsWidth[col], Height: int32(cellHeight)}
-		rl.DrawRectangleRec(cellRect.ToFloat32(), cellBackgroundColor)
-		if rl.CheckCollisionPointRec(mouse, cellRect.ToFloat32()) {
-			if rl.IsMouseButtonPressed(rl.MouseButtonLeft) {
-				cursor.Position.Col = int32(col)
-				cursor.Position.Row = row
+// spreadsheetCell is laid out content cell, it is everything needed to draw the cell
+type spreadsheetCell struct {
+	rect       rl.RectangleInt32
+	text       string
+	textColor  rl.Color
+	background rl.Color
+	border     rl.Color
+	caretX     float32 // caret position of edited cell, 0 when cell is not edited
+	strike     bool
+}
+
+// layoutContentRow computes cells of visible columns of the row, cells are appended to given slice
+func layoutContentRow(appAssets *assets.Assets, dg *database.DataGrid, cursor *cursor.Cursor, search *mode.Search, layout *spreadsheetLayout, cellY int32, cellHeight int, textPadding int32, row int32, cells []spreadsheetCell) []spreadsheetCell {
+	var isRowInserted bool = dg.IsRowInserted(row)
+	var isRowDeleted bool = dg.IsRowDeleted(row)
+	_, isRowFailed := dg.RowError(row)
+	var diffStatus database.DiffStatus = dg.DiffStatus(row)
+	for _, col := range layout.visibleCols {
+		val := dg.Data[row][dg.Headers[col]]
+		cell := spreadsheetCell{
+			rect:       rl.RectangleInt32{X: layout.columnX(col), Y: cellY, Width: dg.ColumnsWidth[col], Height: int32(cellHeight)},
+			textColor:  config.Get().Colors.Text(),
+			background: config.Get().Colors.Background(),
+			border:     config.Get().Colors.Mantle(),
+		}
+		if cursor.IsFocused(col, row) {
+			cell.background = config.Get().Colors.Mantle()
+			cell.border = config.Get().Colors.Accent()
+		}
+		if cursor.IsActive() && cursor.IsSelected(col, row) {
+			cell.background = config.Get().Colors.Surface1()
+		}
+		if _, isMatch := search.MatchIndex(row, col); isMatch {
+			cell.background = config.Get().Colors.Yellow()
+			cell.textColor = config.Get().Colors.Crust()
+		}
+		switch {
+		case isRowDeleted, diffStatus == database.DiffRemoved:
+			cell.textColor = config.Get().Colors.Red()
+			cell.strike = true
+		case isRowInserted, diffStatus == database.DiffAdded:
+			cell.textColor = config.Get().Colors.Green()
+		}
+		if _, isChanged := val.(database.CellChange); isChanged {
+			cell.textColor = config.Get().Colors.Yellow()
+			if !cursor.IsFocused(col, row) {
+				cell.border = config.Get().Colors.Yellow()
+			}
+		}
+		if dg.IsCellEdited(row, col) {
+			cell.textColor = config.Get().Colors.Peach()
+			if !cursor.IsFocused(col, row) {
+				cell.border = config.Get().Colors.Peach()
 			}
 		}
-		cellText := format.GetValueAsString(val)
+		if isRowFailed && !cursor.IsFocused(col, row) {
+			cell.border = config.Get().Colors.Red()
+		}
+
+		cellText := format.TruncateCell(format.GetDisplayValue(val))
+		var isEditingCell bool = cursor.IsEditingCell(col, row)
+		if isEditingCell {
+			cellText = cursor.Common.EditBuf
+		} else if val == nil {
+			cell.textColor = config.Get().Colors.Overlay0()
+		}
 		var cellTextSliceLimit int = len(cellText)
 		var maxNumberOfCharacters int = int(dg.ColumnsWidth[col] / int32(appAssets.MainFontCharacterWidth))
 		if cellTextSliceLimit > maxNumberOfCharacters {
 			cellTextSliceLimit = maxNumberOfCharacters
 		}
+		if isEditingCell {
+			// Keep end of edited value visible together with caret
+			cellText = cellText[len(cellText)-cellTextSliceLimit:]
+			cell.caretX = float32(cell.rect.X+textPadding) + float32(cellTextSliceLimit)*appAssets.MainFontCharacterWidth
+			cellTextSliceLimit = len(cellText)
+		}
+		cell.text = cellText[:cellTextSliceLimit]
+		cells = append(cells, cell)
+	}
+	return cells
+}
+
+// drawContentRow draws laid out cells, row stays within its own height, so it can be redrawn alone
+func drawContentRow(appAssets *assets.Assets, cells []spreadsheetCell, textPadding int32) {
+	for _, cell := range cells {
+		rl.DrawRectangleRec(cell.rect.ToFloat32(), cell.background)
+		if cell.caretX > 0 {
+			rl.DrawRectangle(int32(cell.caretX), cell.rect.Y+textPadding, 2, int32(appAssets.MainFontSize), config.Get().Colors.InsertMode())
+		}
 		appAssets.DrawTextMainFont(
-			cellText[:cellTextSliceLimit],
-			rl.Vector2{X: float32(cellX + textPadding), Y: float32(cellY + textPadding)},
-			config.Get().Colors.Text(),
+			cell.text,
+			rl.Vector2{X: float32(cell.rect.X + textPadding), Y: float32(cell.rect.Y + textPadding)},
+			cell.textColor,
 		)
+		if cell.strike {
+			var strikeY float32 = float32(cell.rect.Y) + float32(cell.rect.Height)/2
+			rl.DrawLineEx(rl.Vector2{X: float32(cell.rect.X), Y: strikeY}, rl.Vector2{X: float32(cell.rect.X + cell.rect.Width), Y: strikeY}, 1, config.Get().Colors.Red())
+		}
 		rl.DrawRectangleLinesEx(
 			rl.Rectangle{
-				X:      float32(cellX),
-				Y:      float32(cellY),
-				Width:  float32(dg.ColumnsWidth[col]) + 1,
-				Height: float32(cellHeight) + 1,
+				X:      float32(cell.rect.X),
+				Y:      float32(cell.rect.Y),
+				Width:  float32(cell.rect.Width) + 1,
+				Height: float32(cell.rect.Height), // cached rows must not paint over the row below
 			},
 			2,
-			cellBorderColor,
+			cell.border,
 		)
 	}
 }
 
+// handleSpreadsheetCellClick moves cursor to clicked content cell
+func handleSpreadsheetCellClick(z *Zone, dg *database.DataGrid, cursor *cursor.Cursor, layout *spreadsheetLayout, cellHeight int, mouse rl.Vector2) {
+	if !rl.IsMouseButtonPressed(rl.MouseButtonLeft) || !z.MouseInside(mouse) || z.colResize.active {
+		return
+	}
+	if mouse.Y < z.Bounds.Y+float32(cellHeight) || mouse.X < float32(layout.baseX) {
+		return
+	}
+	var row int32 = int32((mouse.Y-z.Bounds.Y+z.Scroll.Y)/float32(cellHeight)) - 1
+	col, ok := layout.columnAt(mouse.X)
+	if !ok || row < 0 || row >= dg.Rows {
+		return
+	}
+	cursor.Position.Col = col
+	cursor.Position.Row = row
+}
+
 func renderSpreadsheetCounterColumnRow(z *Zone, appAssets *assets.Assets, counterColumnWidth int, counterColumnCharactersCount int, cellHeight int, textPadding int32, mouse rl.Vector2, row int32) {
 	var cellX int32 = int32(z.Bounds.X)
 	var cellY int32 = int32(z.Bounds.Y) + (row+1)*int32(cellHeight) - int32(z.Scroll.Y)
@@ -115,28 +201,118 @@ func renderSpreadsheetCounterColumnRow(z *Zone, appAssets *assets.Assets, counte
 	appAssets.DrawTextMainFont(strconv.Itoa(int(row+1)), rl.Vector2{X: float32(cellX) + counterColumnLeftPadding, Y: float32(cellY + textPadding)}, config.Get().Colors.Overlay0())
 }
 
-func renderSpreadsheetHeadersRow(z *Zone, appAssets *assets.Assets, dg *database.DataGrid, counterColumnWidth int, cellHeight int, textPadding int32, mouse rl.Vector2) {
-	for col := int32(0); col < dg.Cols; col++ {
-		var cellX int32 = int32(z.Bounds.X-z.Scroll.X) + int32(counterColumnWidth)
-		for c := int32(0); c < col; c++ {
-			cellX += dg.ColumnsWidth[c]
-		}
+func renderSpreadsheetHeadersRow(z *Zone, appAssets *assets.Assets, dg *database.DataGrid, layout *spreadsheetLayout, cellHeight int, textPadding int32, mouse rl.Vector2) {
+	hoveredCol, isHovered := layout.columnAt(mouse.X)
+	isHovered = isHovered && z.MouseInside(mouse)
+	for _, col := range layout.visibleCols {
+		var cellX int32 = layout.columnX(col)
 		var cellY int32 = int32(z.Bounds.Y)
 
 		var bg rl.Color = config.Get().Colors.Surface0()
-		if z.MouseInside(mouse) && mouse.X > float32(cellX) && mouse.X < float32(cellX)+float32(dg.ColumnsWidth[col]) {
+		if isHovered && hoveredCol == col {
 			bg = config.Get().Colors.Mantle()
+			// Clicking header cycles sorting, with shift column is added as next sort key
+			if mouse.Y < float32(cellY+int32(cellHeight)) && rl.IsMouseButtonPressed(rl.MouseButtonLeft) && !z.colResize.active && !z.colResize.hovering {
+				dg.CycleSort(dg.Headers[col], rl.IsKeyDown(rl.KeyLeftShift) || rl.IsKeyDown(rl.KeyRightShift))
+			}
 		}
 		rl.DrawRectangle(cellX, cellY, dg.ColumnsWidth[col], int32(cellHeight), bg)
 		rl.DrawLineEx(rl.Vector2{X: float32(cellX), Y: float32(cellY)}, rl.Vector2{X: float32(cellX), Y: float32(cellY + int32(cellHeight))}, 2, config.Get().Colors.Surface1())
 		appAssets.DrawTextMainFont(dg.Headers[col], rl.Vector2{X: float32(cellX + textPadding), Y: float32(cellY + textPadding)}, config.Get().Colors.Text())
+
+		if position, descending := dg.SortDirection(dg.Headers[col]); position > 0 {
+			var sortIndicator string = "^"
+			if descending {
+				sortIndicator = "v"
+			}
+			if len(dg.SortKeys) > 1 {
+				sortIndicator += strconv.Itoa(position)
+			}
+			var indicatorX float32 = float32(cellX+dg.ColumnsWidth[col]-textPadding) - float32(len(sortIndicator))*appAssets.MainFontCharacterWidth
+			appAssets.DrawTextMainFont(sortIndicator, rl.Vector2{X: indicatorX, Y: float32(cellY + textPadding)}, config.Get().Colors.Accent())
+		}
+	}
+}
+
+// columnResize tracks dragging of header border, width is saved when mouse is released
+type columnResize struct {
+	active        bool
+	hovering      bool
+	col           int32
+	grabX         float32
+	startWidth    int32
+	lastClickTime float64
+	lastClickCol  int32
+}
+
+// handleSpreadsheetColumnResize resizes column by dragging right border of its header, double click fits column to content
+func handleSpreadsheetColumnResize(z *Zone, dg *database.DataGrid, layout *spreadsheetLayout, cellHeight int, mouse rl.Vector2) {
+	const borderGrabDistance float32 = 4
+	const doubleClickInterval float64 = 0.4
+	z.colResize.hovering = false
+	if z.colResize.active {
+		rl.SetMouseCursor(rl.MouseCursorResizeEW)
+		dg.ResizeColumn(z.colResize.col, z.colResize.startWidth+int32(mouse.X-z.colResize.grabX))
+		if rl.IsMouseButtonReleased(rl.MouseButtonLeft) {
+			z.colResize.active = false
+			if err := dg.SaveColumnsWidth(); err != nil {
+				slog.Error("Failed to save column widths", slog.Any("error", err))
+			}
+		}
+		return
+	}
+
+	if !z.MouseInside(mouse) || mouse.Y > z.Bounds.Y+float32(cellHeight) {
+		return
+	}
+	for _, col := range slices.Backward(layout.visibleCols) {
+		var borderX float32 = float32(layout.columnX(col) + dg.ColumnsWidth[col])
+		if mouse.X < borderX-borderGrabDistance || mouse.X > borderX+borderGrabDistance {
+			continue
+		}
+		rl.SetMouseCursor(rl.MouseCursorResizeEW)
+		z.colResize.hovering = true
+		if !rl.IsMouseButtonPressed(rl.MouseButtonLeft) {
+			return
+		}
+		if z.colResize.lastClickCol == col && rl.GetTime()-z.colResize.lastClickTime < doubleClickInterval {
+			dg.FitColumnWidth(col)
+			if err := dg.SaveColumnsWidth(); err != nil {
+				slog.Error("Failed to save column widths", slog.Any("error", err))
+			}
+			z.colResize.lastClickTime = 0
+			return
+		}
+		z.colResize = columnResize{
+			active:        true,
+			col:           col,
+			grabX:         mouse.X,
+			startWidth:    dg.ColumnsWidth[col],
+			lastClickTime: rl.GetTime(),
+			lastClickCol:  col,
+		}
+		return
 	}
 }
 
-func updateSpreadsheetScrollBasedOnCursor(z *Zone, dg *database.DataGrid, cursor *cursor.Cursor, cellHeight int, linesPadding int8) (scrollRow int32, lastRowToRender int32) {
+func renderSpreadsheetFilterBar(z *Zone, appAssets *assets.Assets, dg *database.DataGrid, cellHeight int, textPadding int32) {
+	var barY int32 = int32(z.Bounds.Y+z.Bounds.Height) - int32(cellHeight)
+	rl.DrawRectangle(int32(z.Bounds.X), barY, int32(z.Bounds.Width), int32(cellHeight), config.Get().Colors.Mantle())
+	rl.DrawLineEx(rl.Vector2{X: z.Bounds.X, Y: float32(barY)}, rl.Vector2{X: z.Bounds.X + z.Bounds.Width, Y: float32(barY)}, 2, config.Get().Colors.Accent())
+
+	var rowsText string = fmt.Sprintf("%d of %d rows", dg.Rows, dg.TotalRows())
+	var rowsTextX float32 = z.Bounds.X + z.Bounds.Width - float32(textPadding) - float32(len(rowsText))*appAssets.MainFontCharacterWidth
+	appAssets.DrawTextMainFont(rowsText, rl.Vector2{X: rowsTextX, Y: float32(barY + textPadding)}, config.Get().Colors.Accent())
+
+	var filterText string = "Filter: " + dg.Filter.Expression
+	var maxNumberOfCharacters int = int((rowsTextX-z.Bounds.X)/appAssets.MainFontCharacterWidth) - 2
+	appAssets.DrawTextMainFont(truncateText(filterText, maxNumberOfCharacters), rl.Vector2{X: z.Bounds.X + float32(textPadding), Y: float32(barY + textPadding)}, config.Get().Colors.Text())
+}
+
+func updateSpreadsheetScrollBasedOnCursor(z *Zone, dg *database.DataGrid, cursor *cursor.Cursor, offsets []int32, cellHeight int, linesPadding int8) (scrollRow int32, lastRowToRender int32) {
 	z.Scroll.X = 0
-	for col := int32(0); col < cursor.Position.Col; col++ {
-		z.Scroll.X += float32(dg.ColumnsWidth[col])
+	if col := min(cursor.Position.Col, dg.Cols); col > dg.FrozenCols {
+		z.Scroll.X = float32(offsets[col] - offsets[dg.FrozenCols])
 	}
 
 	var rowsToRender int8 = z.GetNumberOfVisibleRows(int32(cellHeight)) + 1
@@ -160,6 +336,7 @@ func updateSpreadsheetScrollBasedOnCursor(z *Zone, dg *database.DataGrid, cursor
 	scrollRow = min(max(scrollRow, 0), cursor.Position.MaxRow)
 	z.ClampScrollsToZoneSize()
 	lastRowToRender = min(dg.Rows, scrollRow+int32(rowsToRender))
+	updateCursorViewRows(cursor, scrollRow, rowsToRender, linesPadding)
 
 	return scrollRow, lastRowToRender
 }
@@ -0,0 +1,182 @@
+package display
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/quar15/qq-go/internal/assets"
+	"github.com/quar15/qq-go/internal/config"
+	"github.com/quar15/qq-go/internal/cursor"
+	"github.com/quar15/qq-go/internal/database"
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+// spreadsheetLayout is horizontal geometry of spreadsheet for single frame.
+// Offsets are prefix sums of column widths, so position of any column is O(1) and column under point O(log cols).
+type spreadsheetLayout struct {
+	offsets     []int32 // offsets[col] is sum of widths of preceding columns, offsets[Cols] is total width
+	baseX       int32   // left edge of first column, right after counter column
+	scrollX     int32
+	frozenCols  int32
+	frozenEdgeX int32
+	visibleCols []int32 // columns intersecting zone, scrolled before frozen ones so frozen are drawn on top
+}
+
+func spreadsheetColumnOffsets(dg *database.DataGrid) []int32 {
+	offsets := make([]int32, dg.Cols+1)
+	for col := int32(0); col < dg.Cols; col++ {
+		offsets[col+1] = offsets[col] + dg.ColumnsWidth[col]
+	}
+	return offsets
+}
+
+func newSpreadsheetLayout(z *Zone, dg *database.DataGrid, offsets []int32, counterColumnWidth int32) spreadsheetLayout {
+	l := spreadsheetLayout{
+		offsets:    offsets,
+		baseX:      int32(z.Bounds.X) + counterColumnWidth,
+		scrollX:    int32(z.Scroll.X),
+		frozenCols: min(dg.FrozenCols, dg.Cols),
+	}
+	l.frozenEdgeX = l.baseX + offsets[l.frozenCols]
+	var rightX int32 = int32(z.Bounds.X + z.Bounds.Width)
+
+	// First scrolled column which is not fully covered by frozen ones
+	first := l.frozenCols + int32(sort.Search(int(dg.Cols-l.frozenCols), func(i int) bool {
+		return l.baseX+offsets[l.frozenCols+int32(i)+1]-l.scrollX > l.frozenEdgeX
+	}))
+	for col := first; col < dg.Cols && l.columnX(col) < rightX; col++ {
+		l.visibleCols = append(l.visibleCols, col)
+	}
+	for col := int32(0); col < l.frozenCols && l.columnX(col) < rightX; col++ {
+		l.visibleCols = append(l.visibleCols, col)
+	}
+	return l
+}
+
+// columnX returns left edge of column on screen, frozen columns do not scroll horizontally
+func (l *spreadsheetLayout) columnX(col int32) int32 {
+	if col < l.frozenCols {
+		return l.baseX + l.offsets[col]
+	}
+	return l.baseX + l.offsets[col] - l.scrollX
+}
+
+// columnAt returns column under horizontal screen position
+func (l *spreadsheetLayout) columnAt(x float32) (int32, bool) {
+	if x < float32(l.baseX) {
+		return 0, false
+	}
+	var contentX int32 = int32(x) - l.baseX
+	var from, to int32 = 0, l.frozenCols
+	if x >= float32(l.frozenEdgeX) {
+		contentX += l.scrollX
+		from, to = l.frozenCols, int32(len(l.offsets)-1)
+	}
+	col := from + int32(sort.Search(int(to-from), func(i int) bool {
+		return l.offsets[from+int32(i)+1] > contentX
+	}))
+	return col, col < to
+}
+
+// spreadsheetRowCache keeps content rows rendered in texture covering the zone.
+// Every frame rows are laid out, only rows whose layout hash changed are drawn again.
+type spreadsheetRowCache struct {
+	texture   rl.RenderTexture2D
+	loaded    bool
+	frame     spreadsheetFrameKey
+	rowHashes map[int32]uint64
+	cells     []spreadsheetCell
+}
+
+// spreadsheetFrameKey invalidates whole texture, e.g. after scrolling or resizing of the zone
+type spreadsheetFrameKey struct {
+	bounds   rl.Rectangle
+	scrollY  float32
+	firstRow int32
+	lastRow  int32
+}
+
+func (z *Zone) drawCachedContentRows(appAssets *assets.Assets, dg *database.DataGrid, cursor *cursor.Cursor, search *mode.Search, layout *spreadsheetLayout, cellHeight int, textPadding int32, scrollRow int32, lastRowToRender int32) {
+	c := &z.rowCache
+	var width, height int32 = int32(z.Bounds.Width), int32(z.Bounds.Height)
+	if width <= 0 || height <= 0 {
+		return
+	}
+	if c.loaded && (c.texture.Texture.Width != width || c.texture.Texture.Height != height) {
+		rl.UnloadRenderTexture(c.texture)
+		c.loaded = false
+	}
+	if !c.loaded {
+		c.texture = rl.LoadRenderTexture(width, height)
+		c.loaded = true
+		c.frame = spreadsheetFrameKey{}
+	}
+
+	frame := spreadsheetFrameKey{bounds: z.Bounds, scrollY: z.Scroll.Y, firstRow: scrollRow, lastRow: lastRowToRender}
+	var redrawAll bool = frame != c.frame || c.rowHashes == nil
+	if redrawAll {
+		c.frame = frame
+		c.rowHashes = make(map[int32]uint64, lastRowToRender-scrollRow)
+	}
+
+	rl.BeginTextureMode(c.texture)
+	// Texture is drawn in screen coordinates of the zone
+	rl.BeginMode2D(rl.Camera2D{Offset: rl.Vector2{X: -z.Bounds.X, Y: -z.Bounds.Y}, Zoom: 1})
+	if redrawAll {
+		rl.ClearBackground(config.Get().Colors.Background())
+	}
+	for row := scrollRow; row < lastRowToRender; row++ {
+		var cellY int32 = int32(z.Bounds.Y) + (row+1)*int32(cellHeight) - int32(z.Scroll.Y)
+		c.cells = layoutContentRow(appAssets, dg, cursor, search, layout, cellY, cellHeight, textPadding, row, c.cells[:0])
+		hash := hashContentRow(c.cells)
+		if prev, ok := c.rowHashes[row]; ok && prev == hash {
+			continue
+		}
+		c.rowHashes[row] = hash
+		rl.DrawRectangle(int32(z.Bounds.X), cellY, width, int32(cellHeight), config.Get().Colors.Background())
+		drawContentRow(appAssets, c.cells, textPadding)
+	}
+	rl.EndMode2D()
+	rl.EndTextureMode()
+
+	// Render textures are stored upside down
+	rl.DrawTextureRec(
+		c.texture.Texture,
+		rl.Rectangle{X: 0, Y: 0, Width: float32(width), Height: -float32(height)},
+		rl.Vector2{X: z.Bounds.X, Y: z.Bounds.Y},
+		rl.White,
+	)
+}
+
+// UnloadSpreadsheetCache frees texture of cached spreadsheet rows
+func (z *Zone) UnloadSpreadsheetCache() {
+	if z.rowCache.loaded {
+		rl.UnloadRenderTexture(z.rowCache.texture)
+		z.rowCache = spreadsheetRowCache{}
+	}
+}
+
+func hashContentRow(cells []spreadsheetCell) uint64 {
+	h := fnv.New64a()
+	var buf [16]byte
+	for _, cell := range cells {
+		binary.LittleEndian.PutUint32(buf[0:], uint32(cell.rect.X))
+		binary.LittleEndian.PutUint32(buf[4:], uint32(cell.rect.Y))
+		binary.LittleEndian.PutUint32(buf[8:], uint32(cell.rect.Width))
+		binary.LittleEndian.PutUint32(buf[12:], uint32(cell.caretX))
+		h.Write(buf[:])
+		h.Write([]byte{
+			cell.textColor.R, cell.textColor.G, cell.textColor.B, cell.textColor.A,
+			cell.background.R, cell.background.G, cell.background.B, cell.background.A,
+			cell.border.R, cell.border.G, cell.border.B, cell.border.A,
+		})
+		if cell.strike {
+			h.Write([]byte{1})
+		}
+		h.Write([]byte(cell.text))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
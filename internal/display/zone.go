@@ -2,6 +2,7 @@ package display
 
 import (
 	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/quar15/qq-go/internal/cursor"
 )
 
 type Zone struct {
@@ -10,6 +11,8 @@ type Zone struct {
 	ContentSize rl.Vector2
 	vScrollbar  Scrollbar
 	hScrollbar  Scrollbar
+	colResize   columnResize
+	rowCache    spreadsheetRowCache
 }
 
 func (z *Zone) MouseInside(mouse rl.Vector2) bool {
@@ -103,3 +106,17 @@ func (z *Zone) UpdateZoneScroll() {
 
 	z.ClampScrollsToZoneSize()
 }
+
+// updateCursorViewRows tells cursor which rows it reaches without scrolling, used by `H`, `M` and `L`
+func updateCursorViewRows(c *cursor.Cursor, scrollRow int32, rowsToRender int8, linesPadding int8) {
+	var top int32 = scrollRow
+	if scrollRow > 0 {
+		top += int32(linesPadding)
+	}
+	var bottom int32 = scrollRow + int32(rowsToRender) - 2
+	if bottom < c.Position.MaxRow {
+		bottom -= int32(linesPadding)
+	}
+	c.Position.ViewBottomRow = max(min(bottom, c.Position.MaxRow), 0)
+	c.Position.ViewTopRow = min(top, c.Position.ViewBottomRow)
+}
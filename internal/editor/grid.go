@@ -19,9 +19,11 @@ type Grid struct {
 	Cols      []int32
 	Highlight [][]HighlightColorEnum
 	MaxCol    int32
+	history   history
 }
 
-func NewGrid() *Grid {
+// NewGrid creates grid with one empty line, undoLevels limits number of changes kept for undo (0 is unlimited)
+func NewGrid(undoLevels int) *Grid {
 	highlight := make([][]HighlightColorEnum, 1)
 	highlight = append(highlight, make([]HighlightColorEnum, 0))
 
@@ -31,6 +33,7 @@ func NewGrid() *Grid {
 		Cols:      []int32{0},
 		Highlight: highlight,
 		MaxCol:    0,
+		history:   history{levels: undoLevels},
 	}
 }
 
@@ -68,8 +71,8 @@ func (eg *Grid) FakeInit(appAssets *assets.Assets) {
 	eg.UpdateHighlight(0, eg.Rows-1)
 }
 
-func LoadGridFromTextFile(path string, appAssets *assets.Assets) (*Grid, error) {
-	var eg *Grid = &Grid{}
+func LoadGridFromTextFile(path string, appAssets *assets.Assets, undoLevels int) (*Grid, error) {
+	var eg *Grid = &Grid{history: history{levels: undoLevels}}
 
 	f, err := os.Open(path)
 	if err != nil {
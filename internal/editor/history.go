@@ -0,0 +1,76 @@
+package editor
+
+import "slices"
+
+// undoState is text of grid before change together with cursor position to restore
+type undoState struct {
+	text []string
+	row  int32
+	col  int32
+}
+
+// history keeps undo and redo states, states share strings of lines so they are cheap to copy
+type history struct {
+	levels int // maximum number of undo states, 0 keeps all of them
+	undo   []undoState
+	redo   []undoState
+}
+
+// SaveUndoState remembers text before change as one undo step, whole insert session should save state only once
+func (eg *Grid) SaveUndoState(row, col int32) {
+	eg.mu.Lock()
+	defer eg.mu.Unlock()
+	state := eg.state(row, col)
+	if n := len(eg.history.undo); n > 0 && slices.Equal(eg.history.undo[n-1].text, state.text) {
+		// Previous step did not change anything, e.g. insert mode left without typing
+		eg.history.undo[n-1] = state
+	} else {
+		eg.history.undo = append(eg.history.undo, state)
+	}
+	eg.history.redo = nil
+
+	if over := len(eg.history.undo) - eg.history.levels; eg.history.levels > 0 && over > 0 {
+		eg.history.undo = slices.Delete(eg.history.undo, 0, over)
+	}
+}
+
+// Undo restores text before last change, returns cursor position from before the change
+func (eg *Grid) Undo(row, col int32) (newRow, newCol int32, ok bool) {
+	eg.mu.Lock()
+	defer eg.mu.Unlock()
+	current := eg.state(row, col)
+	for n := len(eg.history.undo); n > 0; n = len(eg.history.undo) {
+		state := eg.history.undo[n-1]
+		eg.history.undo = eg.history.undo[:n-1]
+		if slices.Equal(state.text, current.text) {
+			continue
+		}
+		eg.history.redo = append(eg.history.redo, current)
+		eg.restore(state)
+		return state.row, state.col, true
+	}
+	return row, col, false
+}
+
+// Redo brings back change reverted by Undo, returns cursor position from before the undo
+func (eg *Grid) Redo(row, col int32) (newRow, newCol int32, ok bool) {
+	eg.mu.Lock()
+	defer eg.mu.Unlock()
+	n := len(eg.history.redo)
+	if n == 0 {
+		return row, col, false
+	}
+	state := eg.history.redo[n-1]
+	eg.history.redo = eg.history.redo[:n-1]
+	eg.history.undo = append(eg.history.undo, eg.state(row, col))
+	eg.restore(state)
+	return state.row, state.col, true
+}
+
+func (eg *Grid) state(row, col int32) undoState {
+	return undoState{text: slices.Clone(eg.Text), row: row, col: col}
+}
+
+func (eg *Grid) restore(state undoState) {
+	eg.replaceLines(0, eg.Rows-1, slices.Clone(state.text))
+}
@@ -0,0 +1,246 @@
+package editor
+
+import "strings"
+
+type RangeKind int8
+
+const (
+	RangeCharwise RangeKind = iota
+	RangeLinewise
+	RangeBlockwise
+)
+
+// Range is part of text operator works on, start and end are inclusive
+type Range struct {
+	StartRow int32
+	StartCol int32
+	EndRow   int32
+	EndCol   int32
+	Kind     RangeKind
+}
+
+// RangeText returns text of range split into lines
+func (eg *Grid) RangeText(r Range) []string {
+	switch r.Kind {
+	case RangeLinewise:
+		return eg.Lines(r.StartRow, r.EndRow)
+	case RangeBlockwise:
+		return eg.Block(r.StartRow, r.EndRow, r.StartCol, r.EndCol)
+	default:
+		return eg.TextRange(r.StartRow, r.StartCol, r.EndRow, r.EndCol)
+	}
+}
+
+// TextRange returns text between two positions (both inclusive) split into lines, as selected in VISUAL mode
+func (eg *Grid) TextRange(startRow, startCol, endRow, endCol int32) []string {
+	eg.mu.RLock()
+	defer eg.mu.RUnlock()
+	lines := make([]string, 0, endRow-startRow+1)
+	for row := startRow; row <= endRow; row++ {
+		line := eg.Text[row]
+		from, to := int32(0), int32(len(line))
+		if row == startRow {
+			from = min(startCol, to)
+		}
+		if row == endRow {
+			to = min(endCol+1, to)
+		}
+		lines = append(lines, line[from:max(from, to)])
+	}
+	return lines
+}
+
+// Lines returns whole lines between rows (both inclusive), as selected in V-LINE mode
+func (eg *Grid) Lines(startRow, endRow int32) []string {
+	eg.mu.RLock()
+	defer eg.mu.RUnlock()
+	return append([]string{}, eg.Text[startRow:endRow+1]...)
+}
+
+// Block returns columns of rows (both inclusive), as selected in V-BLOCK mode. Short lines give shorter text.
+func (eg *Grid) Block(startRow, endRow, startCol, endCol int32) []string {
+	eg.mu.RLock()
+	defer eg.mu.RUnlock()
+	lines := make([]string, 0, endRow-startRow+1)
+	for row := startRow; row <= endRow; row++ {
+		line := eg.Text[row]
+		from := min(startCol, int32(len(line)))
+		to := min(endCol+1, int32(len(line)))
+		lines = append(lines, line[from:to])
+	}
+	return lines
+}
+
+// InsertText puts lines at position like typed text, returns position right after inserted text
+func (eg *Grid) InsertText(row, col int32, lines []string) (endRow, endCol int32) {
+	eg.mu.Lock()
+	defer eg.mu.Unlock()
+	line := eg.Text[row]
+	col = min(col, int32(len(line)))
+	before, after := line[:col], line[col:]
+
+	newLines := append([]string{}, lines...)
+	newLines[0] = before + newLines[0]
+	last := len(newLines) - 1
+	endCol = int32(len(newLines[last]))
+	newLines[last] += after
+	eg.replaceLines(row, row, newLines)
+
+	return row + int32(last), endCol
+}
+
+// InsertLines puts lines above row, row equal to number of rows appends them at the end
+func (eg *Grid) InsertLines(row int32, lines []string) {
+	eg.mu.Lock()
+	defer eg.mu.Unlock()
+	eg.replaceLines(row, row-1, lines)
+}
+
+// InsertBlock puts lines as rectangle starting at position, short lines are padded with spaces and missing lines added
+func (eg *Grid) InsertBlock(row, col int32, lines []string) {
+	eg.mu.Lock()
+	defer eg.mu.Unlock()
+	var width int
+	for _, l := range lines {
+		width = max(width, len(l))
+	}
+
+	var missing []string
+	for int(eg.Rows) < int(row)+len(lines)+len(missing) {
+		missing = append(missing, "")
+	}
+	if len(missing) > 0 {
+		eg.replaceLines(eg.Rows, eg.Rows-1, missing)
+	}
+
+	newLines := make([]string, len(lines))
+	for i, l := range lines {
+		line := eg.Text[row+int32(i)]
+		if int32(len(line)) < col {
+			line += strings.Repeat(" ", int(col)-len(line))
+		}
+		inserted := l
+		if int32(len(line)) > col {
+			// Text after block stays aligned
+			inserted += strings.Repeat(" ", width-len(l))
+		}
+		newLines[i] = line[:col] + inserted + line[col:]
+	}
+	eg.replaceLines(row, row+int32(len(lines))-1, newLines)
+}
+
+// DeleteRange removes text of range, grid always keeps at least one line
+func (eg *Grid) DeleteRange(r Range) {
+	eg.mu.Lock()
+	defer eg.mu.Unlock()
+	switch r.Kind {
+	case RangeLinewise:
+		var lines []string
+		if r.StartRow == 0 && r.EndRow >= eg.Rows-1 {
+			lines = []string{""}
+		}
+		eg.replaceLines(r.StartRow, r.EndRow, lines)
+	case RangeBlockwise:
+		lines := make([]string, 0, r.EndRow-r.StartRow+1)
+		for row := r.StartRow; row <= r.EndRow; row++ {
+			line := eg.Text[row]
+			from := min(r.StartCol, int32(len(line)))
+			to := min(r.EndCol+1, int32(len(line)))
+			lines = append(lines, line[:from]+line[to:])
+		}
+		eg.replaceLines(r.StartRow, r.EndRow, lines)
+	default:
+		first, last := eg.Text[r.StartRow], eg.Text[r.EndRow]
+		from := min(r.StartCol, int32(len(first)))
+		to := min(r.EndCol+1, int32(len(last)))
+		eg.replaceLines(r.StartRow, r.EndRow, []string{first[:from] + last[to:]})
+	}
+}
+
+// ReplaceLines swaps rows between start and end (both inclusive) with lines
+func (eg *Grid) ReplaceLines(startRow, endRow int32, lines []string) {
+	eg.mu.Lock()
+	defer eg.mu.Unlock()
+	eg.replaceLines(startRow, endRow, lines)
+}
+
+// MapRange replaces text of range with result of fn applied to each of its lines, fn should keep length of text
+func (eg *Grid) MapRange(r Range, fn func(string) string) {
+	eg.mu.Lock()
+	defer eg.mu.Unlock()
+	lines := make([]string, 0, r.EndRow-r.StartRow+1)
+	for row := r.StartRow; row <= r.EndRow; row++ {
+		line := eg.Text[row]
+		from, to := int32(0), int32(len(line))
+		switch r.Kind {
+		case RangeBlockwise:
+			from, to = min(r.StartCol, to), min(r.EndCol+1, to)
+		case RangeCharwise:
+			if row == r.StartRow {
+				from = min(r.StartCol, to)
+			}
+			if row == r.EndRow {
+				to = min(r.EndCol+1, to)
+			}
+		}
+		to = max(from, to)
+		lines = append(lines, line[:from]+fn(line[from:to])+line[to:])
+	}
+	eg.replaceLines(r.StartRow, r.EndRow, lines)
+}
+
+// ShiftLines indents rows by width spaces, negative width removes up to that much of leading white space
+func (eg *Grid) ShiftLines(startRow, endRow int32, width int) {
+	eg.mu.Lock()
+	defer eg.mu.Unlock()
+	lines := make([]string, 0, endRow-startRow+1)
+	for row := startRow; row <= endRow; row++ {
+		line := eg.Text[row]
+		switch {
+		case width > 0 && line != "":
+			line = strings.Repeat(" ", width) + line
+		case width < 0:
+			var removed int
+			for removed < -width && removed < len(line) && (line[removed] == ' ' || line[removed] == '\t') {
+				removed++
+			}
+			line = line[removed:]
+		}
+		lines = append(lines, line)
+	}
+	eg.replaceLines(startRow, endRow, lines)
+}
+
+// Indentation returns column of first non blank character of row
+func (eg *Grid) Indentation(row int32) int32 {
+	eg.mu.RLock()
+	defer eg.mu.RUnlock()
+	line := eg.Text[row]
+	return int32(len(line) - len(strings.TrimLeft(line, " \t")))
+}
+
+// replaceLines swaps rows from-to (both inclusive, to before from inserts) with lines and keeps columns and highlight in sync
+func (eg *Grid) replaceLines(from, to int32, lines []string) {
+	tail := append([]string{}, eg.Text[to+1:]...)
+	eg.Text = append(append(eg.Text[:from], lines...), tail...)
+
+	tailCols := append([]int32{}, eg.Cols[to+1:]...)
+	eg.Cols = eg.Cols[:from]
+	for _, l := range lines {
+		eg.Cols = append(eg.Cols, int32(len(l)))
+	}
+	eg.Cols = append(eg.Cols, tailCols...)
+
+	tailHighlight := append([][]HighlightColorEnum{}, eg.Highlight[to+1:]...)
+	eg.Highlight = append(append(eg.Highlight[:from], make([][]HighlightColorEnum, len(lines))...), tailHighlight...)
+
+	eg.Rows = int32(len(eg.Text))
+	if len(lines) > 0 {
+		eg.UpdateHighlight(from, from+int32(len(lines))-1)
+	}
+
+	eg.MaxCol = 0
+	for _, col := range eg.Cols {
+		eg.MaxCol = max(eg.MaxCol, col)
+	}
+}
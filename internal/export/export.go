@@ -0,0 +1,335 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/quar15/qq-go/internal/format"
+)
+
+type Format int8
+
+const (
+	FormatCSV Format = iota
+	FormatTSV
+	FormatJSON
+	FormatNDJSON
+	FormatMarkdown
+	FormatSQLInsert
+	FormatSQLIn
+)
+
+var formatName = map[Format]string{
+	FormatCSV:       "csv",
+	FormatTSV:       "tsv",
+	FormatJSON:      "json",
+	FormatNDJSON:    "ndjson",
+	FormatMarkdown:  "markdown",
+	FormatSQLInsert: "sql",
+	FormatSQLIn:     "in",
+}
+
+var formatAliases = map[string]Format{
+	"csv":      FormatCSV,
+	"tsv":      FormatTSV,
+	"tab":      FormatTSV,
+	"json":     FormatJSON,
+	"ndjson":   FormatNDJSON,
+	"jsonl":    FormatNDJSON,
+	"md":       FormatMarkdown,
+	"markdown": FormatMarkdown,
+	"sql":      FormatSQLInsert,
+	"insert":   FormatSQLInsert,
+	"in":       FormatSQLIn,
+}
+
+func (f Format) String() string {
+	return formatName[f]
+}
+
+func ParseFormat(name string) (Format, error) {
+	f, ok := formatAliases[strings.ToLower(strings.TrimPrefix(name, "."))]
+	if !ok {
+		return FormatCSV, fmt.Errorf("Unknown export format: %s", name)
+	}
+	return f, nil
+}
+
+func FormatFromPath(path string) (Format, error) {
+	ext := filepath.Ext(path)
+	if ext == "" {
+		return FormatCSV, fmt.Errorf("Cannot detect export format of '%s'", path)
+	}
+	return ParseFormat(ext)
+}
+
+type Options struct {
+	Table          []string // schema qualified target table for INSERT statements
+	IncludeHeaders bool     // header row for CSV/TSV
+}
+
+// Write serializes rows in requested format. Every row must have the same length as headers.
+func Write(w io.Writer, f Format, headers []string, rows [][]any, opts Options) error {
+	switch f {
+	case FormatCSV:
+		return writeDelimited(w, ',', headers, rows, opts)
+	case FormatTSV:
+		return writeTSV(w, headers, rows, opts)
+	case FormatJSON:
+		return writeJSON(w, headers, rows)
+	case FormatNDJSON:
+		return writeNDJSON(w, headers, rows)
+	case FormatMarkdown:
+		return writeMarkdown(w, headers, rows)
+	case FormatSQLInsert:
+		return writeSQLInsert(w, headers, rows, opts)
+	case FormatSQLIn:
+		return writeSQLIn(w, headers, rows)
+	default:
+		return fmt.Errorf("Unsupported export format: %d", f)
+	}
+}
+
+func WriteFile(path string, f Format, headers []string, rows [][]any, opts Options) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(file)
+	if err := Write(bw, f, headers, rows, opts); err != nil {
+		file.Close()
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeDelimited produces RFC-4180 output (quotes fields with delimiter, quotes or newlines)
+func writeDelimited(w io.Writer, delimiter rune, headers []string, rows [][]any, opts Options) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = delimiter
+	cw.UseCRLF = true
+	if opts.IncludeHeaders {
+		if err := cw.Write(headers); err != nil {
+			return err
+		}
+	}
+	record := make([]string, len(headers))
+	for _, row := range rows {
+		for i, val := range row {
+			record[i] = format.GetExportValue(val)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeTSV replaces tabs and newlines inside values, so output pastes cleanly into spreadsheets
+func writeTSV(w io.Writer, headers []string, rows [][]any, opts Options) error {
+	escaper := strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+	record := make([]string, len(headers))
+	if opts.IncludeHeaders {
+		for i, h := range headers {
+			record[i] = escaper.Replace(h)
+		}
+		if _, err := io.WriteString(w, strings.Join(record, "\t")+"\n"); err != nil {
+			return err
+		}
+	}
+	for _, row := range rows {
+		for i, val := range row {
+			record[i] = escaper.Replace(format.GetExportValue(val))
+		}
+		if _, err := io.WriteString(w, strings.Join(record, "\t")+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, headers []string, rows [][]any) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	for i, row := range rows {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		if _, err := io.WriteString(w, "\n  "); err != nil {
+			return err
+		}
+		if err := writeJSONObject(w, headers, row); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "\n]\n")
+	return err
+}
+
+func writeNDJSON(w io.Writer, headers []string, rows [][]any) error {
+	for _, row := range rows {
+		if err := writeJSONObject(w, headers, row); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeJSONObject keeps column order of the grid, which map based encoding would lose
+func writeJSONObject(w io.Writer, headers []string, row []any) error {
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i, h := range headers {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		key, err := json.Marshal(h)
+		if err != nil {
+			return err
+		}
+		sb.Write(key)
+		sb.WriteByte(':')
+		val, err := json.Marshal(jsonValue(row[i]))
+		if err != nil {
+			return err
+		}
+		sb.Write(val)
+	}
+	sb.WriteByte('}')
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func jsonValue(val any) any {
+	switch val := val.(type) {
+	case nil, bool, string,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64,
+		map[string]any, []any, json.RawMessage:
+		return val
+	default:
+		return format.GetValueAsString(val)
+	}
+}
+
+func writeMarkdown(w io.Writer, headers []string, rows [][]any) error {
+	escaper := strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>")
+	var sb strings.Builder
+	sb.WriteString("|")
+	for _, h := range headers {
+		sb.WriteString(" " + escaper.Replace(h) + " |")
+	}
+	sb.WriteString("\n|")
+	for range headers {
+		sb.WriteString(" --- |")
+	}
+	sb.WriteString("\n")
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		sb.Reset()
+		sb.WriteString("|")
+		for _, val := range row {
+			sb.WriteString(" " + escaper.Replace(format.GetExportValue(val)) + " |")
+		}
+		sb.WriteString("\n")
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeSQLInsert(w io.Writer, headers []string, rows [][]any, opts Options) error {
+	table := pgx.Identifier(opts.Table)
+	if len(table) == 0 {
+		table = pgx.Identifier{"export"}
+	}
+	columns := make([]string, len(headers))
+	for i, h := range headers {
+		columns[i] = pgx.Identifier{h}.Sanitize()
+	}
+	prefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES (", table.Sanitize(), strings.Join(columns, ", "))
+
+	values := make([]string, len(headers))
+	for _, row := range rows {
+		for i, val := range row {
+			values[i] = SQLLiteral(val)
+		}
+		if _, err := io.WriteString(w, prefix+strings.Join(values, ", ")+");\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSQLIn produces list for `IN` condition without duplicates, rows of several columns become tuples:
+// IN (1, 2) or IN ((1, 'a'), (2, 'b'))
+func writeSQLIn(w io.Writer, headers []string, rows [][]any) error {
+	items := make([]string, 0, len(rows))
+	seen := make(map[string]bool, len(rows))
+	values := make([]string, len(headers))
+	for _, row := range rows {
+		for i, val := range row {
+			values[i] = SQLLiteral(val)
+		}
+		item := strings.Join(values, ", ")
+		if len(values) > 1 {
+			item = "(" + item + ")"
+		}
+		if !seen[item] {
+			seen[item] = true
+			items = append(items, item)
+		}
+	}
+	_, err := io.WriteString(w, "IN ("+strings.Join(items, ", ")+")\n")
+	return err
+}
+
+// SQLLiteral renders value as SQL literal usable in generated statements
+func SQLLiteral(val any) string {
+	switch val := val.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if val {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", val)
+	case float32:
+		return strconv.FormatFloat(float64(val), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64)
+	case time.Time:
+		return "'" + val.Format(time.RFC3339Nano) + "'"
+	default:
+		return "'" + strings.ReplaceAll(format.GetValueAsString(val), "'", "''") + "'"
+	}
+}
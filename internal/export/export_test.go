@@ -0,0 +1,159 @@
+package export
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	headers := []string{"id", "name", "data"}
+	rows := [][]any{
+		{int64(1), "plain", json.RawMessage(`{"a":[1,2]}`)},
+		{int64(2), "with, comma\tand \"quote\"\nline", nil},
+		{int64(1), "plain", nil},
+	}
+	tests := []struct {
+		name   string
+		format Format
+		opts   Options
+		want   string
+	}{
+		{
+			name:   "csv",
+			format: FormatCSV,
+			opts:   Options{IncludeHeaders: true},
+			want: "id,name,data\r\n" +
+				"1,plain,\"{\"\"a\"\":[1,2]}\"\r\n" +
+				"2,\"with, comma\tand \"\"quote\"\"\r\nline\",\r\n" +
+				"1,plain,\r\n",
+		},
+		{
+			name:   "tsv",
+			format: FormatTSV,
+			want: "1\tplain\t{\"a\":[1,2]}\n" +
+				"2\twith, comma and \"quote\" line\t\n" +
+				"1\tplain\t\n",
+		},
+		{
+			name:   "json",
+			format: FormatJSON,
+			want: "[\n" +
+				"  {\"id\":1,\"name\":\"plain\",\"data\":{\"a\":[1,2]}},\n" +
+				"  {\"id\":2,\"name\":\"with, comma\\tand \\\"quote\\\"\\nline\",\"data\":null},\n" +
+				"  {\"id\":1,\"name\":\"plain\",\"data\":null}\n" +
+				"]\n",
+		},
+		{
+			name:   "ndjson",
+			format: FormatNDJSON,
+			want: "{\"id\":1,\"name\":\"plain\",\"data\":{\"a\":[1,2]}}\n" +
+				"{\"id\":2,\"name\":\"with, comma\\tand \\\"quote\\\"\\nline\",\"data\":null}\n" +
+				"{\"id\":1,\"name\":\"plain\",\"data\":null}\n",
+		},
+		{
+			name:   "markdown",
+			format: FormatMarkdown,
+			want: "| id | name | data |\n" +
+				"| --- | --- | --- |\n" +
+				"| 1 | plain | {\"a\":[1,2]} |\n" +
+				"| 2 | with, comma\tand \"quote\"<br>line |  |\n" +
+				"| 1 | plain |  |\n",
+		},
+		{
+			name:   "sql insert",
+			format: FormatSQLInsert,
+			opts:   Options{Table: []string{"public", "MyTable"}},
+			want: "INSERT INTO \"public\".\"MyTable\" (\"id\", \"name\", \"data\") VALUES (1, 'plain', '{\"a\":[1,2]}');\n" +
+				"INSERT INTO \"public\".\"MyTable\" (\"id\", \"name\", \"data\") VALUES (2, 'with, comma\tand \"quote\"\nline', NULL);\n" +
+				"INSERT INTO \"public\".\"MyTable\" (\"id\", \"name\", \"data\") VALUES (1, 'plain', NULL);\n",
+		},
+		{
+			name:   "sql in",
+			format: FormatSQLIn,
+			want:   "IN ((1, 'plain', '{\"a\":[1,2]}'), (2, 'with, comma\tand \"quote\"\nline', NULL), (1, 'plain', NULL))\n",
+		},
+	}
+	for _, tt := range tests {
+		var sb strings.Builder
+		if err := Write(&sb, tt.format, headers, rows, tt.opts); err != nil {
+			t.Errorf("%s: %s", tt.name, err)
+			continue
+		}
+		if got := sb.String(); got != tt.want {
+			t.Errorf("%s:\n got %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWriteSQLInWithoutDuplicates(t *testing.T) {
+	var sb strings.Builder
+	rows := [][]any{{int64(1)}, {int64(2)}, {int64(1)}, {nil}}
+	if err := Write(&sb, FormatSQLIn, []string{"id"}, rows, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sb.String(), "IN (1, 2, NULL)\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestWriteSQLInsertDefaultTable(t *testing.T) {
+	var sb strings.Builder
+	if err := Write(&sb, FormatSQLInsert, []string{"n"}, [][]any{{"it's"}}, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sb.String(), "INSERT INTO \"export\" (\"n\") VALUES ('it''s');\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSQLLiteral(t *testing.T) {
+	tests := []struct {
+		val  any
+		want string
+	}{
+		{nil, "NULL"},
+		{true, "TRUE"},
+		{false, "FALSE"},
+		{int32(-5), "-5"},
+		{1.5, "1.5"},
+		{float32(0.25), "0.25"},
+		{"O'Brien", "'O''Brien'"},
+		{time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC), "'2024-05-01T10:30:00Z'"},
+	}
+	for _, tt := range tests {
+		if got := SQLLiteral(tt.val); got != tt.want {
+			t.Errorf("SQLLiteral(%v) = %s, want %s", tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		want Format
+	}{
+		{"csv", FormatCSV},
+		{".TSV", FormatTSV},
+		{"jsonl", FormatNDJSON},
+		{"md", FormatMarkdown},
+		{"insert", FormatSQLInsert},
+		{"in", FormatSQLIn},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.name)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseFormat(%q) = %s, %v, want %s", tt.name, got, err, tt.want)
+		}
+	}
+	if _, err := ParseFormat("xlsx"); err == nil {
+		t.Error("ParseFormat(xlsx) succeeded")
+	}
+	if got, err := FormatFromPath("out/result.ndjson"); err != nil || got != FormatNDJSON {
+		t.Errorf("FormatFromPath = %s, %v, want ndjson", got, err)
+	}
+	if _, err := FormatFromPath("result"); err == nil {
+		t.Error("FormatFromPath without extension succeeded")
+	}
+}
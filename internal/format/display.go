@@ -0,0 +1,202 @@
+package format
+
+import (
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DisplayOptions controls how values are presented in spreadsheet, clipboard and exports (`display:` in config)
+type DisplayOptions struct {
+	FloatPrecision      *int   `yaml:"float_precision,omitempty"`       // digits after decimal point, shortest exact value when not set
+	ThousandsSeparator  string `yaml:"thousands_separator,omitempty"`   // e.g. "," or " ", none when empty
+	DateTimeLayout      string `yaml:"datetime_layout,omitempty"`       // Go time layout of timestamps with time zone
+	LocalDateTimeLayout string `yaml:"local_datetime_layout,omitempty"` // Go time layout of timestamps without time zone
+	DateLayout          string `yaml:"date_layout,omitempty"`           // Go time layout of dates
+	TimeZone            string `yaml:"time_zone,omitempty"`             // zone of timestamptz: "utc", "local" or IANA name, as received when empty
+	NullText            string `yaml:"null,omitempty"`                  // placeholder rendered instead of NULL
+	MaxCellLength       int    `yaml:"max_cell_length,omitempty"`       // longer values are cut in spreadsheet, 0 disables
+}
+
+var (
+	displayMu       sync.RWMutex
+	displayOptions  DisplayOptions = DisplayOptions{}.WithDefaults()
+	displayLocation *time.Location
+)
+
+func (o DisplayOptions) WithDefaults() DisplayOptions {
+	if o.DateTimeLayout == "" {
+		o.DateTimeLayout = timestampLayout
+	}
+	if o.LocalDateTimeLayout == "" {
+		o.LocalDateTimeLayout = localTimestampLayout
+	}
+	if o.DateLayout == "" {
+		o.DateLayout = dateLayout
+	}
+	if o.NullText == "" {
+		o.NullText = NullText
+	}
+	return o
+}
+
+func SetDisplayOptions(opts DisplayOptions) {
+	opts = opts.WithDefaults()
+	var location *time.Location
+	switch strings.ToLower(opts.TimeZone) {
+	case "":
+	case "utc":
+		location = time.UTC
+	case "local":
+		location = time.Local
+	default:
+		loc, err := time.LoadLocation(opts.TimeZone)
+		if err != nil {
+			slog.Error("Unknown display time zone, keeping received zones", slog.String("timeZone", opts.TimeZone), slog.Any("error", err))
+		}
+		location = loc
+	}
+
+	displayMu.Lock()
+	defer displayMu.Unlock()
+	displayOptions = opts
+	displayLocation = location
+}
+
+func GetDisplayOptions() DisplayOptions {
+	displayMu.RLock()
+	defer displayMu.RUnlock()
+	return displayOptions
+}
+
+// GetDisplayValue formats value using display options, NULL is rendered as configured placeholder
+func GetDisplayValue(val any) string {
+	opts := GetDisplayOptions()
+	if val == nil {
+		return opts.NullText
+	}
+	return formatDisplayValue(val, opts)
+}
+
+// GetExportValue formats value using display options, NULL is exported as empty text
+func GetExportValue(val any) string {
+	if val == nil {
+		return ""
+	}
+	return formatDisplayValue(val, GetDisplayOptions())
+}
+
+// TruncateCell cuts text longer than configured maximum cell length
+func TruncateCell(text string) string {
+	maxLength := GetDisplayOptions().MaxCellLength
+	if maxLength <= 0 || utf8.RuneCountInString(text) <= maxLength {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:max(maxLength-3, 0)]) + "..."
+}
+
+func formatDisplayValue(val any, opts DisplayOptions) string {
+	switch v := val.(type) {
+	case int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64:
+		return groupThousands(fmt.Sprintf("%d", v), opts.ThousandsSeparator)
+	case float32:
+		return groupThousands(formatFloat(float64(v), 32, opts), opts.ThousandsSeparator)
+	case float64:
+		return groupThousands(formatFloat(v, 64, opts), opts.ThousandsSeparator)
+	case pgtype.Numeric:
+		if opts.FloatPrecision != nil && v.Valid && !v.NaN && v.InfinityModifier == pgtype.Finite {
+			return groupThousands(roundNumeric(v, *opts.FloatPrecision), opts.ThousandsSeparator)
+		}
+		return groupThousands(formatNumeric(v), opts.ThousandsSeparator)
+	case time.Time:
+		// Only timestamptz is loaded as time.Time, it is the instant which can be shown in display time zone
+		displayMu.RLock()
+		location := displayLocation
+		displayMu.RUnlock()
+		if location != nil {
+			v = v.In(location)
+		}
+		return v.Format(opts.DateTimeLayout)
+	case pgtype.Timestamp:
+		// Wall clock time without time zone, converting it would shift it by offset of display time zone
+		return formatTimestamp(v, opts.LocalDateTimeLayout)
+	case pgtype.Date:
+		// Dates have no time zone, so they are not converted to display one
+		if v.Valid && v.InfinityModifier == pgtype.Finite {
+			return v.Time.Format(opts.DateLayout)
+		}
+		return formatDate(v)
+	default:
+		return GetValueAsString(val)
+	}
+}
+
+func formatFloat(f float64, bitSize int, opts DisplayOptions) string {
+	if opts.FloatPrecision != nil {
+		return strconv.FormatFloat(f, 'f', *opts.FloatPrecision, bitSize)
+	}
+	return strconv.FormatFloat(f, 'f', -1, bitSize)
+}
+
+// roundNumeric rounds numeric half away from zero to given number of decimal places
+func roundNumeric(n pgtype.Numeric, precision int) string {
+	if int(-n.Exp) <= precision {
+		text := formatNumeric(n)
+		if precision == 0 {
+			return text
+		}
+		if !strings.Contains(text, ".") {
+			text += "."
+		}
+		decimals := len(text) - strings.Index(text, ".") - 1
+		return text + strings.Repeat("0", precision-decimals)
+	}
+
+	// Drop digits beyond precision, rounding on the first dropped one
+	drop := int(-n.Exp) - precision
+	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(drop)), nil)
+	quotient, remainder := new(big.Int).QuoRem(new(big.Int).Abs(n.Int), divisor, new(big.Int))
+	if remainder.Mul(remainder, big.NewInt(2)).Cmp(divisor) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	if n.Int.Sign() < 0 {
+		quotient.Neg(quotient)
+	}
+	return formatNumeric(pgtype.Numeric{Int: quotient, Exp: int32(-precision), Valid: true})
+}
+
+// groupThousands inserts separator into integer part of formatted number
+func groupThousands(number string, separator string) string {
+	if separator == "" {
+		return number
+	}
+	sign := ""
+	if strings.HasPrefix(number, "-") {
+		sign, number = "-", number[1:]
+	}
+	integer, fraction := number, ""
+	if idx := strings.IndexByte(number, '.'); idx >= 0 {
+		integer, fraction = number[:idx], number[idx:]
+	}
+	if len(integer) <= 3 || strings.ContainsFunc(integer, func(r rune) bool { return r < '0' || r > '9' }) {
+		return sign + number
+	}
+
+	var sb strings.Builder
+	for i, r := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			sb.WriteString(separator)
+		}
+		sb.WriteRune(r)
+	}
+	return sign + sb.String() + fraction
+}
@@ -0,0 +1,87 @@
+package format
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func setTestDisplayOptions(t *testing.T, opts DisplayOptions) {
+	t.Helper()
+	SetDisplayOptions(opts)
+	t.Cleanup(func() { SetDisplayOptions(DisplayOptions{}) })
+}
+
+func TestGetDisplayValue(t *testing.T) {
+	precision := 2
+	setTestDisplayOptions(t, DisplayOptions{
+		FloatPrecision:      &precision,
+		ThousandsSeparator:  ",",
+		LocalDateTimeLayout: "02.01.2006 15:04",
+		DateLayout:          "02.01.2006",
+		TimeZone:            "utc",
+		NullText:            "∅",
+	})
+
+	warsaw := time.FixedZone("CEST", 2*60*60)
+	tests := []struct {
+		name string
+		val  any
+		want string
+	}{
+		{"NULL", nil, "∅"},
+		{"integer", int64(1234567), "1,234,567"},
+		{"short integer", int32(-123), "-123"},
+		{"float", -1234.5, "-1,234.50"},
+		{"numeric rounded", pgtype.Numeric{Int: big.NewInt(12345678), Exp: -4, Valid: true}, "1,234.57"},
+		{"numeric rounded away from zero", pgtype.Numeric{Int: big.NewInt(-125), Exp: -3, Valid: true}, "-0.13"},
+		{"numeric padded", pgtype.Numeric{Int: big.NewInt(5), Exp: 0, Valid: true}, "5.00"},
+		{"numeric NaN", pgtype.Numeric{NaN: true, Valid: true}, "NaN"},
+		{"timestamptz in display zone", time.Date(2024, 5, 1, 10, 30, 0, 0, warsaw), "2024-05-01 08:30:00+00:00"},
+		{"timestamp kept as received", pgtype.Timestamp{Time: time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC), Valid: true}, "01.05.2024 10:30"},
+		{"date", pgtype.Date{Time: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Valid: true}, "01.05.2024"},
+		{"infinite date", pgtype.Date{InfinityModifier: pgtype.Infinity, Valid: true}, "infinity"},
+		{"text", "1234567", "1234567"},
+		{"array", []any{int64(1), nil}, "{1,∅}"},
+	}
+	for _, tt := range tests {
+		if got := GetDisplayValue(tt.val); got != tt.want {
+			t.Errorf("%s: GetDisplayValue(%v) = %q, want %q", tt.name, tt.val, got, tt.want)
+		}
+	}
+	if got := GetExportValue(nil); got != "" {
+		t.Errorf("GetExportValue(nil) = %q, want empty text", got)
+	}
+}
+
+func TestGetDisplayValueKeepsReceivedZone(t *testing.T) {
+	setTestDisplayOptions(t, DisplayOptions{})
+
+	warsaw := time.FixedZone("CEST", 2*60*60)
+	if got, want := GetDisplayValue(time.Date(2024, 5, 1, 10, 30, 0, 0, warsaw)), "2024-05-01 10:30:00+02:00"; got != want {
+		t.Errorf("GetDisplayValue = %q, want %q", got, want)
+	}
+	if got, want := GetDisplayValue(1234567.125), "1234567.125"; got != want {
+		t.Errorf("GetDisplayValue = %q, want %q", got, want)
+	}
+}
+
+func TestTruncateCell(t *testing.T) {
+	setTestDisplayOptions(t, DisplayOptions{MaxCellLength: 5})
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"abcde", "abcde"},
+		{"abcdef", "ab..."},
+		{"żółtość", "żó..."},
+	}
+	for _, tt := range tests {
+		if got := TruncateCell(tt.text); got != tt.want {
+			t.Errorf("TruncateCell(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
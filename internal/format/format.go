@@ -4,10 +4,28 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"math/big"
+	"net"
+	"net/netip"
+	"reflect"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// NullText is default placeholder rendered instead of NULL values, so they are distinct from empty strings
+const NullText string = "NULL"
+
+const timestampLayout string = "2006-01-02 15:04:05.999999999-07:00"
+const localTimestampLayout string = "2006-01-02 15:04:05.999999999"
+const dateLayout string = "2006-01-02"
+
+// Types without formatter are reported only once, not for every cell
+var unhandledTypes sync.Map
+
 func CountDigits(n int) int {
 	var count int = 0
 
@@ -34,23 +52,232 @@ func GetValueAsString(val any) string {
 		return val
 	case []byte:
 		return string(val)
+	case json.RawMessage:
+		return string(val)
 	case int, int8, int16, int32, int64,
 		uint, uint8, uint16, uint32, uint64:
 		return fmt.Sprintf("%d", val)
-	case float32, float64:
-		return fmt.Sprintf("%f", val)
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
 	case bool:
 		return strconv.FormatBool(val)
 	case time.Time:
-		return val.Format("2006-01-02 15:04:05")
-	case map[string]any, []any:
+		return val.Format(timestampLayout)
+	case pgtype.Date:
+		return formatDate(val)
+	case pgtype.Timestamp:
+		return formatTimestamp(val, localTimestampLayout)
+	case [16]uint8:
+		return formatUUID(val)
+	case pgtype.Numeric:
+		return formatNumeric(val)
+	case pgtype.Interval:
+		return formatInterval(val)
+	case pgtype.Time:
+		return formatTimeOfDay(val)
+	case netip.Prefix:
+		// inet of single host is shown without mask, like psql does
+		if val.IsSingleIP() {
+			return val.Addr().String()
+		}
+		return val.String()
+	case netip.Addr:
+		return val.String()
+	case net.HardwareAddr:
+		return val.String()
+	case map[string]any:
 		b, err := json.Marshal(val)
 		if err != nil {
 			return "<invalid json>"
 		}
 		return string(b)
+	case []any:
+		return formatArray(val)
+	case pgtype.Range[any]:
+		return formatRange(val)
+	case pgtype.Multirange[pgtype.Range[any]]:
+		ranges := make([]string, len(val))
+		for i, r := range val {
+			ranges[i] = formatRange(r)
+		}
+		return "{" + strings.Join(ranges, ",") + "}"
+	case fmt.Stringer:
+		return val.String()
 	default:
-		slog.Error("Unhandled type while formatting", slog.Any("type", fmt.Sprintf("%T", val)))
+		if _, logged := unhandledTypes.LoadOrStore(reflect.TypeOf(val), true); !logged {
+			slog.Warn("Unhandled type while formatting", slog.String("type", fmt.Sprintf("%T", val)))
+		}
 		return fmt.Sprintf("%v", val)
 	}
 }
+
+// formatDate renders value of date column, loaded grids keep those as pgtype.Date so they are not mistaken for timestamps
+func formatDate(d pgtype.Date) string {
+	switch {
+	case !d.Valid:
+		return ""
+	case d.InfinityModifier != pgtype.Finite:
+		return d.InfinityModifier.String()
+	default:
+		return d.Time.Format(dateLayout)
+	}
+}
+
+// formatTimestamp renders value of timestamp column without time zone, it has no offset to show
+func formatTimestamp(t pgtype.Timestamp, layout string) string {
+	switch {
+	case !t.Valid:
+		return ""
+	case t.InfinityModifier != pgtype.Finite:
+		return t.InfinityModifier.String()
+	default:
+		return t.Time.Format(layout)
+	}
+}
+
+func formatUUID(u [16]uint8) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
+
+func formatNumeric(n pgtype.Numeric) string {
+	switch {
+	case !n.Valid:
+		return ""
+	case n.NaN:
+		return "NaN"
+	case n.InfinityModifier == pgtype.Infinity:
+		return "Infinity"
+	case n.InfinityModifier == pgtype.NegativeInfinity:
+		return "-Infinity"
+	}
+
+	digits := new(big.Int).Abs(n.Int).String()
+	sign := ""
+	if n.Int.Sign() < 0 {
+		sign = "-"
+	}
+	if n.Exp >= 0 {
+		return sign + digits + strings.Repeat("0", int(n.Exp))
+	}
+
+	scale := int(-n.Exp)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+}
+
+// formatInterval renders interval like postgres does, e.g. "1 year 2 mons 3 days 04:05:06.5"
+func formatInterval(i pgtype.Interval) string {
+	if !i.Valid {
+		return ""
+	}
+
+	var parts []string
+	years, months := i.Months/12, i.Months%12
+	if years != 0 {
+		parts = append(parts, pluralUnit(int64(years), "year", "years"))
+	}
+	if months != 0 {
+		parts = append(parts, pluralUnit(int64(months), "mon", "mons"))
+	}
+	if i.Days != 0 {
+		parts = append(parts, pluralUnit(int64(i.Days), "day", "days"))
+	}
+	if i.Microseconds != 0 || len(parts) == 0 {
+		micros := i.Microseconds
+		sign := ""
+		if micros < 0 {
+			sign = "-"
+			micros = -micros
+		}
+		parts = append(parts, sign+formatClock(micros))
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatTimeOfDay(t pgtype.Time) string {
+	if !t.Valid {
+		return ""
+	}
+	return formatClock(t.Microseconds)
+}
+
+// formatClock renders microseconds as hh:mm:ss with optional fraction
+func formatClock(micros int64) string {
+	const microsPerSecond int64 = 1_000_000
+	hours := micros / (3600 * microsPerSecond)
+	minutes := micros / (60 * microsPerSecond) % 60
+	seconds := micros / microsPerSecond % 60
+	text := fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
+	if fraction := micros % microsPerSecond; fraction != 0 {
+		text += strings.TrimRight(fmt.Sprintf(".%06d", fraction), "0")
+	}
+	return text
+}
+
+func pluralUnit(n int64, singular string, plural string) string {
+	if n == 1 || n == -1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// formatArray renders postgres array literal, e.g. {1,2,NULL}. JSON arrays are loaded as json.RawMessage, not []any.
+func formatArray(values []any) string {
+	nullText := GetDisplayOptions().NullText
+	elements := make([]string, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case nil:
+			elements[i] = nullText
+		case []any:
+			elements[i] = formatArray(v)
+		default:
+			elements[i] = quoteArrayElement(GetValueAsString(v), nullText)
+		}
+	}
+	return "{" + strings.Join(elements, ",") + "}"
+}
+
+// quoteArrayElement quotes element which could be mistaken for NULL or separators of array
+func quoteArrayElement(text string, nullText string) string {
+	if text != "" && !strings.EqualFold(text, NullText) && text != nullText && !strings.ContainsAny(text, "{},\"\\ \t\n") {
+		return text
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text) + `"`
+}
+
+// formatRange renders range with its bounds, e.g. [1,10)
+func formatRange(r pgtype.Range[any]) string {
+	if !r.Valid {
+		return ""
+	}
+	lowerType, upperType := r.LowerType, r.UpperType
+	if lowerType == pgtype.Empty {
+		return "empty"
+	}
+	lower, upper := r.Lower, r.Upper
+
+	var sb strings.Builder
+	if lowerType == pgtype.Inclusive {
+		sb.WriteByte('[')
+	} else {
+		sb.WriteByte('(')
+	}
+	if lowerType != pgtype.Unbounded {
+		sb.WriteString(GetValueAsString(lower))
+	}
+	sb.WriteByte(',')
+	if upperType != pgtype.Unbounded {
+		sb.WriteString(GetValueAsString(upper))
+	}
+	if upperType == pgtype.Inclusive {
+		sb.WriteByte(']')
+	} else {
+		sb.WriteByte(')')
+	}
+	return sb.String()
+}
@@ -0,0 +1,77 @@
+package format
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestGetValueAsString(t *testing.T) {
+	warsaw := time.FixedZone("CEST", 2*60*60)
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		val  any
+		want string
+	}{
+		{"NULL", nil, ""},
+		{"text", "abc", "abc"},
+		{"integer", int32(-42), "-42"},
+		{"float", 1.5, "1.5"},
+		{"float32", float32(0.1), "0.1"},
+		{"boolean", true, "true"},
+		{"json object", json.RawMessage(`{"a":1}`), `{"a":1}`},
+		{"json array", json.RawMessage(`[1,"b",null]`), `[1,"b",null]`},
+		{"timestamptz", time.Date(2024, 5, 1, 10, 30, 0, 500_000_000, warsaw), "2024-05-01 10:30:00.5+02:00"},
+		{"timestamp", pgtype.Timestamp{Time: time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC), Valid: true}, "2024-05-01 10:30:00"},
+		{"infinite timestamp", pgtype.Timestamp{InfinityModifier: pgtype.NegativeInfinity, Valid: true}, "-infinity"},
+		{"date", pgtype.Date{Time: day, Valid: true}, "2024-05-01"},
+		{"infinite date", pgtype.Date{InfinityModifier: pgtype.Infinity, Valid: true}, "infinity"},
+		{"invalid date", pgtype.Date{}, ""},
+		{"uuid", [16]uint8{0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0, 0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0}, "12345678-9abc-def0-1234-56789abcdef0"},
+		{"numeric", pgtype.Numeric{Int: big.NewInt(12345), Exp: -2, Valid: true}, "123.45"},
+		{"numeric with exponent", pgtype.Numeric{Int: big.NewInt(5), Exp: 2, Valid: true}, "500"},
+		{"small numeric", pgtype.Numeric{Int: big.NewInt(-5), Exp: -3, Valid: true}, "-0.005"},
+		{"numeric NaN", pgtype.Numeric{NaN: true, Valid: true}, "NaN"},
+		{"interval", pgtype.Interval{Months: 14, Days: 3, Microseconds: (4*3600+5*60+6)*1_000_000 + 500_000, Valid: true}, "1 year 2 mons 3 days 04:05:06.5"},
+		{"negative interval", pgtype.Interval{Days: -1, Microseconds: -90 * 1_000_000, Valid: true}, "-1 day -00:01:30"},
+		{"zero interval", pgtype.Interval{Valid: true}, "00:00:00"},
+		{"time", pgtype.Time{Microseconds: (13*3600 + 45*60) * 1_000_000, Valid: true}, "13:45:00"},
+		{"inet host", netip.MustParsePrefix("10.0.0.1/32"), "10.0.0.1"},
+		{"cidr", netip.MustParsePrefix("10.0.0.0/24"), "10.0.0.0/24"},
+		{"array", []any{int64(1), nil, "a b", "NULL", "", []any{int64(2), int64(3)}}, `{1,NULL,"a b","NULL","",{2,3}}`},
+		{"range", pgtype.Range[any]{Lower: int64(1), Upper: int64(10), LowerType: pgtype.Inclusive, UpperType: pgtype.Exclusive, Valid: true}, "[1,10)"},
+		{"unbounded range", pgtype.Range[any]{Upper: int64(5), LowerType: pgtype.Unbounded, UpperType: pgtype.Inclusive, Valid: true}, "(,5]"},
+		{"empty range", pgtype.Range[any]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true}, "empty"},
+		{"multirange", pgtype.Multirange[pgtype.Range[any]]{
+			{Lower: int64(1), Upper: int64(3), LowerType: pgtype.Inclusive, UpperType: pgtype.Exclusive, Valid: true},
+			{Lower: int64(5), Upper: int64(7), LowerType: pgtype.Inclusive, UpperType: pgtype.Exclusive, Valid: true},
+		}, "{[1,3),[5,7)}"},
+	}
+	for _, tt := range tests {
+		if got := GetValueAsString(tt.val); got != tt.want {
+			t.Errorf("%s: GetValueAsString(%v) = %q, want %q", tt.name, tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestArrayUsesNullPlaceholder(t *testing.T) {
+	SetDisplayOptions(DisplayOptions{NullText: "∅"})
+	t.Cleanup(func() { SetDisplayOptions(DisplayOptions{}) })
+
+	if got, want := GetValueAsString([]any{"a", nil, "∅"}), `{a,∅,"∅"}`; got != want {
+		t.Errorf("GetValueAsString = %q, want %q", got, want)
+	}
+}
+
+func TestCountDigits(t *testing.T) {
+	for n, want := range map[int]int{0: 1, 7: 1, 10: 2, -999: 3, 123456: 6} {
+		if got := CountDigits(n); got != want {
+			t.Errorf("CountDigits(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package mode
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/quar15/qq-go/internal/database"
+)
+
+// ChartView is chart of current result shown next to spreadsheet, points are rebuilt whenever result changes
+type ChartView struct {
+	Spec    database.ChartSpec
+	visible bool
+	key     chartKey
+	data    database.ChartData
+	err     error
+}
+
+// chartKey identifies state of result chart was built from
+type chartKey struct {
+	data    uintptr
+	rows    int32
+	cols    int32
+	first   uintptr
+	last    uintptr
+	changes int // revision of pending changes
+	filter  string
+	sort    string
+	spec    string
+}
+
+func (c *ChartView) Open(spec database.ChartSpec) {
+	c.Spec = spec
+	c.visible = true
+	c.key = chartKey{}
+}
+
+func (c *ChartView) Close() {
+	c.visible = false
+	c.data = database.ChartData{}
+}
+
+func (c *ChartView) IsVisible() bool {
+	return c != nil && c.visible
+}
+
+// Data returns points of chart for result, they are built again only after result changed (new query, sort, filter, edits...)
+func (c *ChartView) Data(dg *database.DataGrid) (database.ChartData, error) {
+	key := chartKey{
+		data: reflect.ValueOf(dg.Data).Pointer(),
+		rows: dg.Rows,
+		cols: dg.Cols,
+		sort: dg.SortDescription(),
+		spec: c.Spec.Kind.String() + "\x00" + c.Spec.XColumn + "\x00" + strings.Join(c.Spec.Series, "\x00"),
+	}
+	if len(dg.Data) > 0 {
+		key.first = reflect.ValueOf(dg.Data[0]).Pointer()
+		key.last = reflect.ValueOf(dg.Data[len(dg.Data)-1]).Pointer()
+	}
+	if dg.Changes != nil {
+		key.changes = dg.Changes.Revision()
+	}
+	if dg.IsFiltered() {
+		key.filter = dg.Filter.Expression
+	}
+	if key != c.key {
+		c.key = key
+		c.data, c.err = database.BuildChartData(dg, c.Spec)
+	}
+	return c.data, c.err
+}
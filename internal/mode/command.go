@@ -1,6 +1,11 @@
 package mode
 
 import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
 	"github.com/quar15/qq-go/internal/cursor"
 	"github.com/quar15/qq-go/internal/motion"
 )
@@ -8,8 +13,68 @@ import (
 type CommandMode struct{}
 
 func (CommandMode) Handle(ctx *Context, k motion.Key) {
-	if k.Code == motion.KeyEnter {
-		ctx.Cursor.Common.Mode = cursor.ModeNormal
+	switch k.Code {
+	case motion.KeyEsc:
+		ctx.Cursor.Common.CmdBuf = ""
+		ctx.Cursor.TransitionMode(cursor.ModeNormal)
+		return
+	case motion.KeyEnter:
+		cmdLine := ctx.Cursor.Common.CmdBuf
+		prevMode := ctx.Cursor.Common.CmdPrevMode
+		ctx.Cursor.Common.CmdBuf = ""
+		// Commands see selection of the mode they were invoked from
+		ctx.Cursor.TransitionMode(prevMode)
+		switch ctx.Cursor.Common.CmdPrefix {
+		case '/', '?':
+			executeSearch(ctx, cmdLine, ctx.Cursor.Common.CmdPrefix == '?')
+		default:
+			executeCommandLine(ctx, cmdLine)
+		}
+		if ctx.Cursor.Common.Mode == prevMode {
+			ctx.Cursor.TransitionMode(cursor.ModeNormal)
+		}
 		return
+	case motion.KeySpecial:
+		if k.Rune == rl.KeyBackspace {
+			buf := ctx.Cursor.Common.CmdBuf
+			if len(buf) == 0 {
+				ctx.Cursor.TransitionMode(cursor.ModeNormal)
+				return
+			}
+			ctx.Cursor.Common.CmdBuf = buf[:len(buf)-1]
+		}
+		return
+	}
+
+	if k.Modifiers == 0 && k.Rune > 31 && k.Rune < 127 {
+		ctx.Cursor.Common.CmdBuf += string(k.Rune)
+	}
+}
+
+func executeCommandLine(ctx *Context, cmdLine string) {
+	fields := strings.Fields(cmdLine)
+	if len(fields) == 0 {
+		return
+	}
+
+	name, args := fields[0], fields[1:]
+	cmd, ok := ctx.Commands.LookupEx(name)
+	if !ok {
+		slog.Warn("Command Mode | Unknown command", slog.String("name", name))
+		ctx.Cursor.Common.Logs.Log(fmt.Sprintf("ERR: Not an editor command: %s", name))
+		return
+	}
+
+	slog.Debug("Command Mode | Trying to execute command", slog.String("cmd", fmt.Sprintf("%T", cmd)), slog.Any("args", args))
+	var err error
+	if rawCmd, ok := cmd.(RawExCommand); ok {
+		argsText := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(cmdLine), name))
+		err = rawCmd.RunRaw(ctx, argsText)
+	} else {
+		err = cmd.Run(ctx, args)
+	}
+	if err != nil {
+		slog.Error("Command Mode | Failed to execute command", slog.String("cmd", fmt.Sprintf("%T", cmd)), slog.Any("error", err))
+		ctx.Cursor.Common.Logs.Log(fmt.Sprintf("ERR: %s", err))
 	}
 }
@@ -0,0 +1,153 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/quar15/qq-go/internal/config"
+	"github.com/quar15/qq-go/internal/cursor"
+	"github.com/quar15/qq-go/internal/database"
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+var errNoPendingChanges = errors.New("No pending changes")
+
+// ReviewChanges shows statements generated from pending grid changes: `:review`
+type ReviewChanges struct{}
+
+func (ReviewChanges) Run(ctx *mode.Context, args []string) error {
+	return ReviewChanges{}.Execute(ctx)
+}
+
+func (ReviewChanges) Execute(ctx *mode.Context) error {
+	dg := ctx.DataGrid
+	if dg == nil || !dg.IsEditable() || dg.Changes.Len() == 0 {
+		return errNoPendingChanges
+	}
+
+	statements := dg.Statements()
+	var counts [3]int
+	lines := make([]mode.PopupLine, 0, len(statements))
+	for _, statement := range statements {
+		counts[statement.Kind]++
+		marker, color := statementMarker(statement.Kind)
+		lines = append(lines, mode.PopupLine{
+			{Text: marker + " ", Color: color},
+			{Text: statement.Preview(), Color: config.Get().Colors.Text()},
+		})
+	}
+	ctx.Popup.Open(
+		fmt.Sprintf(
+			"Pending changes of %s (+%d ~%d -%d)",
+			dg.Source, counts[database.StatementInsert], counts[database.StatementUpdate], counts[database.StatementDelete],
+		),
+		lines,
+		"c: commit | x: discard | q: close",
+		map[rune]mode.Command{
+			'c': CommitChanges{},
+			'x': DiscardChanges{},
+		},
+	)
+	return nil
+}
+
+// CommitChanges runs pending changes of the grid in single transaction: `:commit`
+type CommitChanges struct{}
+
+func (CommitChanges) Run(ctx *mode.Context, args []string) error {
+	return CommitChanges{}.Execute(ctx)
+}
+
+func (CommitChanges) Execute(ctx *mode.Context) error {
+	dg := ctx.DataGrid
+	if dg == nil || !dg.IsEditable() || dg.Changes.Len() == 0 {
+		return errNoPendingChanges
+	}
+
+	if tab := ctx.Results.CurrentTab(); tab != nil && tab.IsCommitting() {
+		return errors.New("Commit of this result is already running")
+	}
+
+	statements := dg.Statements()
+	results, err := ctx.ConnManager.ExecTransaction(dg.ConnectionName, statements)
+	if err != nil {
+		return err
+	}
+	// Result is applied by main loop to grid of the tab, which is not necessarily current one by then
+	if err := ctx.Results.StartCommit(statements, results); err != nil {
+		return err
+	}
+	ctx.Popup.Close()
+	ctx.Cursor.Common.Logs.Log(fmt.Sprintf("Committing %d statement(s) to '%s'", len(statements), dg.Source))
+	return nil
+}
+
+// DiscardChanges reverts all pending changes of the grid: `:discard`
+type DiscardChanges struct{}
+
+func (DiscardChanges) Run(ctx *mode.Context, args []string) error {
+	return DiscardChanges{}.Execute(ctx)
+}
+
+func (DiscardChanges) Execute(ctx *mode.Context) error {
+	dg := ctx.DataGrid
+	if dg == nil || !dg.IsEditable() || dg.Changes.Len() == 0 {
+		return errNoPendingChanges
+	}
+	dg.Discard()
+	ctx.Popup.Close()
+	ctx.UpdateSpreadsheetPositionMax()
+	ctx.Cursor.Common.Logs.Log("Discarded pending changes")
+	return nil
+}
+
+// SetCellNull sets current cell to NULL as pending change: `:null`
+type SetCellNull struct{}
+
+func (SetCellNull) Run(ctx *mode.Context, args []string) error {
+	if ctx.Cursor.Type != cursor.TypeSpreadsheet {
+		return errors.New("NULL can be set only in spreadsheet")
+	}
+	return ctx.DataGrid.EditCell(ctx.Cursor.Position.Row, ctx.Cursor.Position.Col, nil)
+}
+
+// ToggleRowsDeleted marks current row (or rows of selection) for deletion, `dd`
+type ToggleRowsDeleted struct{}
+
+func (ToggleRowsDeleted) Execute(ctx *mode.Context) error {
+	dg := ctx.DataGrid
+	c := ctx.Cursor
+	if !dg.IsEditable() {
+		c.Common.Logs.Log("Result is not editable (single table with primary key required)")
+		return nil
+	}
+
+	startRow, endRow := c.Position.Row, c.Position.Row
+	if c.Common.Mode == cursor.ModeVisual || c.Common.Mode == cursor.ModeVLine || c.Common.Mode == cursor.ModeVBlock {
+		startRow, endRow = c.Position.SelectStartRow, min(c.Position.SelectEndRow, dg.Rows-1)
+	}
+	// Inserted rows are removed from grid immediately, so go from the bottom to keep indexes valid
+	for row := endRow; row >= startRow; row-- {
+		if err := dg.ToggleRowDeleted(row); err != nil {
+			return err
+		}
+	}
+
+	c.Position.Row = startRow
+	c.Common.Mode = cursor.ModeNormal
+	ctx.Parser.Reset()
+	ctx.UpdateSpreadsheetPositionMax()
+	return nil
+}
+
+func statementMarker(kind database.StatementKind) (string, rl.Color) {
+	switch kind {
+	case database.StatementInsert:
+		return "+", config.Get().Colors.Green()
+	case database.StatementDelete:
+		return "-", config.Get().Colors.Red()
+	default:
+		return "~", config.Get().Colors.Yellow()
+	}
+}
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/quar15/qq-go/internal/database"
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+// ShowChart draws chart of current result next to spreadsheet: `:chart <bar|line|scatter> [<x> [<series>...]]`.
+// X defaults to current column and series to numeric columns, `:chart` toggles the chart and `:chart off` hides it.
+type ShowChart struct{}
+
+func (ShowChart) Run(ctx *mode.Context, args []string) error {
+	const usage string = "Usage: chart <bar|line|scatter> [<x> [<series>...]]"
+	if len(args) == 0 {
+		if ctx.Chart.IsVisible() {
+			ctx.Chart.Close()
+			return nil
+		}
+		if ctx.Chart.Spec.XColumn == "" {
+			return errors.New(usage)
+		}
+		ctx.Chart.Open(ctx.Chart.Spec)
+		return nil
+	}
+	if args[0] == "off" {
+		ctx.Chart.Close()
+		return nil
+	}
+
+	kind, err := database.ParseChartKind(args[0])
+	if err != nil {
+		return err
+	}
+	dg := ctx.DataGrid
+	if dg.Cols == 0 {
+		return errors.New("No result to chart")
+	}
+	spec := database.ChartSpec{Kind: kind, XColumn: dg.Headers[min(ctx.Cursor.Position.Col, dg.Cols-1)]}
+	if len(args) > 1 {
+		spec.XColumn = args[1]
+	}
+	spec.Series = args[min(2, len(args)):]
+	if len(spec.Series) == 0 {
+		spec.Series = database.NumericColumns(dg, spec.XColumn)
+	}
+
+	data, err := database.BuildChartData(dg, spec)
+	if err != nil {
+		return err
+	}
+	ctx.Chart.Open(spec)
+
+	message := fmt.Sprintf("Chart %s of %s by %s", kind, strings.Join(spec.Series, ", "), spec.XColumn)
+	if data.Sampled {
+		message += fmt.Sprintf(" (%d of %d rows sampled)", len(data.Labels), dg.Rows)
+	}
+	ctx.Cursor.Common.Logs.Log(message)
+	return nil
+}
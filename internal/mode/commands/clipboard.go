@@ -1,57 +1,115 @@
 package commands
 
 import (
+	"fmt"
 	"log/slog"
+	"strings"
 
+	"github.com/quar15/qq-go/internal/config"
 	"github.com/quar15/qq-go/internal/cursor"
+	"github.com/quar15/qq-go/internal/export"
 	"github.com/quar15/qq-go/internal/format"
 	"github.com/quar15/qq-go/internal/mode"
 	"golang.design/x/clipboard"
 )
 
-type CopyToClipboardSpreadsheet struct{}
+// CopyToClipboardSpreadsheet copies current cell, or selection in given format. Ctrl+C copies TSV, `gy` opens
+// prompt with all formats and `:copy [tsv|csv|md|json|in|sql] [--headers]` copies in named format.
+type CopyToClipboardSpreadsheet struct {
+	Format  export.Format
+	Headers bool
+}
 type CopyToClipboardEditor struct{}
 
-func (CopyToClipboardSpreadsheet) Execute(ctx *mode.Context) error {
+func (c CopyToClipboardSpreadsheet) Run(ctx *mode.Context, args []string) error {
+	if len(args) == 0 {
+		return CopyFormatPrompt{}.Execute(ctx)
+	}
+	for _, arg := range args {
+		if arg == "--headers" {
+			c.Headers = true
+			continue
+		}
+		f, err := export.ParseFormat(arg)
+		if err != nil {
+			return err
+		}
+		c.Format = f
+	}
+	return c.Execute(ctx)
+}
+
+func (c CopyToClipboardSpreadsheet) Execute(ctx *mode.Context) error {
 	if ctx.Cursor.Type != cursor.TypeSpreadsheet {
 		return nil
 	}
+	ctx.Popup.Close()
 
-	var dataString string = ""
-	c := ctx.Cursor
 	dg := ctx.DataGrid
+	pos := ctx.Cursor.Position
+	if pos.Row >= dg.Rows || pos.Col >= dg.Cols {
+		return nil
+	}
 
+	var headers []string
+	var rows [][]any
 	switch ctx.Cursor.Common.Mode {
-	case cursor.ModeVisual:
-		for row := c.Position.SelectStartRow; row <= c.Position.SelectEndRow; row++ {
-			for col := int32(0); col < dg.Cols; col++ {
-				if c.IsSelected(col, row) {
-					dataString += format.GetValueAsString(dg.Data[row][dg.Headers[col]]) + ","
-				}
-			}
-			dataString += "\n"
-		}
-	case cursor.ModeVLine:
-		for row := c.Position.SelectStartRow; row <= c.Position.SelectEndRow; row++ {
-			for col := int32(0); col < dg.Cols; col++ {
-				dataString += format.GetValueAsString(dg.Data[row][dg.Headers[col]]) + ","
-			}
-			dataString += format.GetValueAsString(dg.Data[row][dg.Headers[c.Position.SelectEndCol]]) + "\n"
-		}
-	case cursor.ModeVBlock:
-		for row := c.Position.SelectStartRow; row <= c.Position.SelectEndRow; row++ {
-			for col := c.Position.SelectStartCol; col < c.Position.SelectEndCol; col++ {
-				dataString += format.GetValueAsString(dg.Data[row][dg.Headers[col]]) + ","
-			}
-			dataString += format.GetValueAsString(dg.Data[row][dg.Headers[c.Position.SelectEndCol]]) + "\n"
+	case cursor.ModeVisual, cursor.ModeVLine, cursor.ModeVBlock:
+		headers, rows = selectedGridData(ctx)
+	default:
+		// Plain copy of single cell keeps value as is, e.g. with its new lines
+		if c.Format == export.FormatTSV && !c.Headers {
+			clipboard.Write(clipboard.FmtText, []byte(format.GetExportValue(dg.Data[pos.Row][dg.Headers[pos.Col]])))
+			ctx.Cursor.Common.Logs.Log("Copied cell to clipboard")
+			return nil
 		}
-	case cursor.ModeNormal:
-		dataString = format.GetValueAsString(dg.Data[c.Position.Row][dg.Headers[c.Position.Col]])
+		headers, rows = []string{dg.Headers[pos.Col]}, [][]any{{dg.Data[pos.Row][dg.Headers[pos.Col]]}}
+	}
+
+	opts := export.Options{IncludeHeaders: c.Headers}
+	if dg.Source != nil {
+		opts.Table = dg.Source.Identifier()
+	}
+	var sb strings.Builder
+	if err := export.Write(&sb, c.Format, headers, rows, opts); err != nil {
+		return err
 	}
 
-	slog.Debug("Copied to clipboard from spreadsheet", slog.String("dataString", dataString))
-	clipboard.Write(clipboard.FmtText, []byte(dataString))
+	slog.Debug("Copied to clipboard from spreadsheet", slog.String("format", c.Format.String()), slog.Int("length", sb.Len()))
+	clipboard.Write(clipboard.FmtText, []byte(sb.String()))
+	ctx.Cursor.Common.Logs.Log(fmt.Sprintf("Copied %d row(s) as %s", len(rows), c.Format))
+	return nil
+}
 
+// CopyFormatPrompt lets user pick format of spreadsheet copy from popup, `gy` or `:copy`
+type CopyFormatPrompt struct{}
+
+func (CopyFormatPrompt) Execute(ctx *mode.Context) error {
+	choices := []struct {
+		key   rune
+		label string
+		cmd   CopyToClipboardSpreadsheet
+	}{
+		{'t', "TSV", CopyToClipboardSpreadsheet{Format: export.FormatTSV}},
+		{'T', "TSV with headers", CopyToClipboardSpreadsheet{Format: export.FormatTSV, Headers: true}},
+		{'c', "CSV (RFC 4180)", CopyToClipboardSpreadsheet{Format: export.FormatCSV}},
+		{'C', "CSV with headers", CopyToClipboardSpreadsheet{Format: export.FormatCSV, Headers: true}},
+		{'m', "Markdown table", CopyToClipboardSpreadsheet{Format: export.FormatMarkdown}},
+		{'J', "JSON array of objects", CopyToClipboardSpreadsheet{Format: export.FormatJSON}},
+		{'i', "SQL IN (...) list", CopyToClipboardSpreadsheet{Format: export.FormatSQLIn}},
+		{'s', "SQL INSERT statements", CopyToClipboardSpreadsheet{Format: export.FormatSQLInsert}},
+	}
+	colors := config.Get().Colors
+	lines := make([]mode.PopupLine, 0, len(choices))
+	actions := make(map[rune]mode.Command, len(choices))
+	for _, choice := range choices {
+		lines = append(lines, mode.PopupLine{
+			{Text: string(choice.key) + "  ", Color: colors.Accent()},
+			{Text: choice.label, Color: colors.Text()},
+		})
+		actions[choice.key] = choice.cmd
+	}
+	ctx.Popup.Open("Copy as", lines, "q: close", actions)
 	return nil
 }
 
@@ -60,42 +118,12 @@ func (CopyToClipboardEditor) Execute(ctx *mode.Context) error {
 		return nil
 	}
 
-	var dataString string = ""
-	c := ctx.Cursor
-	eg := ctx.EditorGrid
-
-	switch ctx.Cursor.Common.Mode {
-	case cursor.ModeVisual:
-		for row := c.Position.SelectStartRow; row <= c.Position.SelectEndRow; row++ {
-			if eg.Cols[row] > 0 {
-				for col := int32(0); col < eg.Cols[row]; col++ {
-					if c.IsSelected(col, row) {
-						dataString += string(eg.Text[row][col])
-					}
-				}
-			}
-			dataString += "\n"
-		}
-	case cursor.ModeVLine:
-		for row := c.Position.SelectStartRow; row <= c.Position.SelectEndRow; row++ {
-			dataString += eg.Text[row] + "\n"
-		}
-	case cursor.ModeVBlock:
-		for row := c.Position.SelectStartRow; row <= c.Position.SelectEndRow; row++ {
-			if eg.Cols[row] > 0 {
-				endCol := min(c.Position.SelectEndCol, eg.Cols[row])
-				for col := c.Position.SelectStartCol; col <= endCol; col++ {
-					dataString += string(eg.Text[row][col])
-				}
-			}
-			dataString += "\n"
-		}
-	default:
-		dataString = eg.Text[c.Position.Row]
+	r, ok := mode.SelectionRange(ctx)
+	if !ok {
+		return nil
 	}
-
-	slog.Debug("Copied to clipboard from editor", slog.String("dataString", dataString))
-	clipboard.Write(clipboard.FmtText, []byte(dataString))
-
-	return nil
+	reg := mode.RangeRegister(ctx.EditorGrid, r)
+	// Clipboard register keeps kind of selection, so `"+p` puts it back the same way
+	slog.Debug("Copied to clipboard from editor", slog.String("dataString", reg.Text()))
+	return ctx.Cursor.Common.Registers.Set(cursor.ClipboardRegister, reg)
 }
@@ -0,0 +1,200 @@
+package commands
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+const columnResizeStep int32 = 24
+
+// ResizeColumn widens (or narrows with negative delta) current column, `+` and `-`
+type ResizeColumn struct {
+	Delta int32
+}
+
+func (r ResizeColumn) Execute(ctx *mode.Context) error {
+	dg := ctx.DataGrid
+	col := ctx.Cursor.Position.Col
+	if col >= dg.Cols {
+		return nil
+	}
+	dg.ResizeColumn(col, dg.ColumnsWidth[col]+r.Delta*columnResizeStep)
+	saveColumnsWidth(ctx)
+	return nil
+}
+
+// SetColumnWidth sets width of current column in pixels: `:width 200`
+type SetColumnWidth struct{}
+
+func (SetColumnWidth) Run(ctx *mode.Context, args []string) error {
+	dg := ctx.DataGrid
+	col := ctx.Cursor.Position.Col
+	if len(args) != 1 {
+		return fmt.Errorf("Usage: width <pixels>")
+	}
+	width, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("Invalid width '%s'", args[0])
+	}
+	if col >= dg.Cols {
+		return nil
+	}
+	dg.ResizeColumn(col, int32(width))
+	saveColumnsWidth(ctx)
+	return nil
+}
+
+// FitColumn sets width of current column to fit its values, `_` or `:fit`
+type FitColumn struct{}
+
+func (FitColumn) Run(ctx *mode.Context, args []string) error {
+	return FitColumn{}.Execute(ctx)
+}
+
+func (FitColumn) Execute(ctx *mode.Context) error {
+	if ctx.Cursor.Position.Col >= ctx.DataGrid.Cols {
+		return nil
+	}
+	ctx.DataGrid.FitColumnWidth(ctx.Cursor.Position.Col)
+	saveColumnsWidth(ctx)
+	return nil
+}
+
+// FitAllColumns sets width of every column to fit its values: `:fitall`
+type FitAllColumns struct{}
+
+func (FitAllColumns) Run(ctx *mode.Context, args []string) error {
+	ctx.DataGrid.FitAllColumnsWidth()
+	saveColumnsWidth(ctx)
+	return nil
+}
+
+func saveColumnsWidth(ctx *mode.Context) {
+	if err := ctx.DataGrid.SaveColumnsWidth(); err != nil {
+		slog.Error("Failed to save column widths", slog.Any("error", err))
+		ctx.Cursor.Common.Logs.Log(fmt.Sprintf("ERR: Failed to save column widths (%s)", err))
+	}
+}
+
+// HideColumn hides current column, `zh`, or named columns: `:hide <column>...`
+type HideColumn struct{}
+
+func (HideColumn) Run(ctx *mode.Context, args []string) error {
+	if len(args) == 0 {
+		return HideColumn{}.Execute(ctx)
+	}
+	for _, header := range args {
+		if err := ctx.DataGrid.HideColumn(header); err != nil {
+			return err
+		}
+	}
+	ctx.UpdateSpreadsheetPositionMax()
+	logHiddenColumns(ctx)
+	return nil
+}
+
+func (HideColumn) Execute(ctx *mode.Context) error {
+	dg := ctx.DataGrid
+	if ctx.Cursor.Position.Col >= dg.Cols {
+		return nil
+	}
+	if err := dg.HideColumn(dg.Headers[ctx.Cursor.Position.Col]); err != nil {
+		ctx.Cursor.Common.Logs.Log(err.Error())
+		return nil
+	}
+	ctx.UpdateSpreadsheetPositionMax()
+	logHiddenColumns(ctx)
+	return nil
+}
+
+// UnhideColumns shows all hidden columns, `zu`, or named ones: `:unhide [<column>...]`
+type UnhideColumns struct{}
+
+func (UnhideColumns) Run(ctx *mode.Context, args []string) error {
+	if err := ctx.DataGrid.UnhideColumns(args...); err != nil {
+		return err
+	}
+	ctx.UpdateSpreadsheetPositionMax()
+	logHiddenColumns(ctx)
+	return nil
+}
+
+func (UnhideColumns) Execute(ctx *mode.Context) error {
+	return UnhideColumns{}.Run(ctx, nil)
+}
+
+func logHiddenColumns(ctx *mode.Context) {
+	hidden := ctx.DataGrid.HiddenHeaders()
+	if len(hidden) == 0 {
+		ctx.Cursor.Common.Logs.Log("All columns visible")
+		return
+	}
+	ctx.Cursor.Common.Logs.Log(fmt.Sprintf("Hidden %d column(s): %s", len(hidden), strings.Join(hidden, ", ")))
+}
+
+// MoveColumn moves current column left or right together with cursor, `zH` and `zL`
+type MoveColumn struct {
+	Offset int32
+}
+
+func (m MoveColumn) Execute(ctx *mode.Context) error {
+	ctx.Cursor.Position.Col = ctx.DataGrid.MoveColumn(ctx.Cursor.Position.Col, m.Offset)
+	return nil
+}
+
+// FreezeColumns keeps columns up to the current one in place while scrolling right, `zf` or `:freeze [<count>]`
+type FreezeColumns struct{}
+
+func (FreezeColumns) Run(ctx *mode.Context, args []string) error {
+	if len(args) == 0 {
+		return FreezeColumns{}.Execute(ctx)
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 0 {
+		return fmt.Errorf("Invalid number of columns '%s'", args[0])
+	}
+	ctx.DataGrid.FreezeColumns(int32(n))
+	logFrozenColumns(ctx)
+	return nil
+}
+
+func (FreezeColumns) Execute(ctx *mode.Context) error {
+	ctx.DataGrid.FreezeColumns(ctx.Cursor.Position.Col + 1)
+	logFrozenColumns(ctx)
+	return nil
+}
+
+// UnfreezeColumns scrolls all columns again, `zF` or `:unfreeze`
+type UnfreezeColumns struct{}
+
+func (UnfreezeColumns) Run(ctx *mode.Context, args []string) error {
+	return UnfreezeColumns{}.Execute(ctx)
+}
+
+func (UnfreezeColumns) Execute(ctx *mode.Context) error {
+	ctx.DataGrid.FreezeColumns(0)
+	logFrozenColumns(ctx)
+	return nil
+}
+
+func logFrozenColumns(ctx *mode.Context) {
+	if ctx.DataGrid.FrozenCols == 0 {
+		ctx.Cursor.Common.Logs.Log("Columns unfrozen")
+		return
+	}
+	ctx.Cursor.Common.Logs.Log(fmt.Sprintf("Frozen %d column(s)", ctx.DataGrid.FrozenCols))
+}
+
+// ResetColumns restores original order and visibility of columns: `:resetcolumns`
+type ResetColumns struct{}
+
+func (ResetColumns) Run(ctx *mode.Context, args []string) error {
+	ctx.DataGrid.ResetColumns()
+	ctx.UpdateSpreadsheetPositionMax()
+	ctx.Cursor.Common.Logs.Log("Columns restored")
+	return nil
+}
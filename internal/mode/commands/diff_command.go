@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/quar15/qq-go/internal/database"
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+// DiffResults compares result of other tab (old) with current one (new) and opens differences in new tab:
+// `:diff <tab> [<key>[,<key>...]|--rows]`. Without key rows are matched by shared primary key, or as whole rows.
+type DiffResults struct{}
+
+func (DiffResults) Run(ctx *mode.Context, args []string) error {
+	const usage string = "Usage: diff <tab> [<key>[,<key>...]|--rows]"
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New(usage)
+	}
+	number, err := strconv.Atoi(args[0])
+	if err != nil || number < 1 || number > len(ctx.Results.Tabs) {
+		return fmt.Errorf("Invalid tab number '%s'", args[0])
+	}
+	if number-1 == ctx.Results.Current {
+		return errors.New("Cannot compare result with itself")
+	}
+
+	oldIdx := number - 1
+	oldGrid := ctx.Results.Grid(oldIdx)
+	newGrid := ctx.DataGrid
+	keys := sharedPrimaryKey(oldGrid, newGrid)
+	if len(args) == 2 {
+		keys = nil
+		if args[1] != "--rows" {
+			keys = strings.Split(args[1], ",")
+		}
+	}
+
+	diff, summary, err := database.DiffGrids(oldGrid, newGrid, keys)
+	if err != nil {
+		return err
+	}
+	label := fmt.Sprintf("diff %d..%d", oldIdx+1, ctx.Results.Current+1)
+	ctx.Results.OpenInNewTab(diff, label, newGrid.ConnectionName)
+
+	matchedBy := "whole rows"
+	if len(keys) > 0 {
+		matchedBy = strings.Join(keys, ", ")
+	}
+	ctx.Cursor.Common.Logs.Log(fmt.Sprintf("Diff by %s: %s", matchedBy, summary))
+	return nil
+}
+
+// sharedPrimaryKey returns primary key of table both results come from
+func sharedPrimaryKey(oldGrid *database.DataGrid, newGrid *database.DataGrid) []string {
+	if oldGrid.Source == nil || newGrid.Source == nil || oldGrid.Source.String() != newGrid.Source.String() {
+		return nil
+	}
+	if !slices.Equal(oldGrid.Source.PrimaryKey, newGrid.Source.PrimaryKey) {
+		return nil
+	}
+	return oldGrid.Source.PrimaryKey
+}
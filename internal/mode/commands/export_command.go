@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/quar15/qq-go/internal/cursor"
+	"github.com/quar15/qq-go/internal/export"
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+// ExportDataGrid writes whole grid or current selection to file: `:export <path> [--format=<fmt>] [--table=<name>]`.
+// Hidden and moved columns are respected unless `--all-columns` is given.
+type ExportDataGrid struct{}
+
+func (ExportDataGrid) Run(ctx *mode.Context, args []string) error {
+	if ctx.Cursor.Type != cursor.TypeSpreadsheet {
+		return errors.New("Export is available only from spreadsheet")
+	}
+
+	var (
+		path       string
+		formatName string
+		allColumns bool
+		opts       = export.Options{IncludeHeaders: true}
+	)
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "--format="):
+			formatName = strings.TrimPrefix(arg, "--format=")
+		case strings.HasPrefix(arg, "--table="):
+			opts.Table = strings.Split(strings.TrimPrefix(arg, "--table="), ".")
+		case arg == "--no-headers":
+			opts.IncludeHeaders = false
+		case arg == "--all-columns":
+			allColumns = true
+		case path == "":
+			path = arg
+		default:
+			return fmt.Errorf("Unexpected argument: %s", arg)
+		}
+	}
+	if path == "" {
+		return errors.New("Usage: :export <path> [--format=csv|tsv|json|ndjson|md|sql|in] [--table=<name>] [--no-headers] [--all-columns]")
+	}
+
+	var (
+		f   export.Format
+		err error
+	)
+	if formatName != "" {
+		f, err = export.ParseFormat(formatName)
+	} else {
+		f, err = export.FormatFromPath(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	headers, rows := selectedGridData(ctx)
+	if allColumns {
+		headers, rows = selectedRowsAllColumns(ctx)
+	}
+	if len(headers) == 0 {
+		return errors.New("Nothing to export")
+	}
+
+	logs := &ctx.Cursor.Common.Logs
+	logs.Log(fmt.Sprintf("Exporting %d row(s) to '%s'", len(rows), path))
+	go func() {
+		if err := export.WriteFile(path, f, headers, rows, opts); err != nil {
+			slog.Error("Export failed", slog.String("path", path), slog.Any("error", err))
+			logs.Log(fmt.Sprintf("ERR: Export to '%s' failed (%s)", path, err))
+			return
+		}
+		slog.Info("Exported data grid", slog.String("path", path), slog.String("format", f.String()), slog.Int("rows", len(rows)))
+		logs.Log(fmt.Sprintf("Exported %d row(s) to '%s' as %s", len(rows), path, f))
+	}()
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/quar15/qq-go/internal/database"
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+// FilterDataGrid narrows rows of the grid with filter expression: `:filter amount > 100 and status = 'paid'`.
+// Without expression filter is cleared.
+type FilterDataGrid struct{}
+
+func (FilterDataGrid) Run(ctx *mode.Context, args []string) error {
+	return FilterDataGrid{}.RunRaw(ctx, strings.Join(args, " "))
+}
+
+func (FilterDataGrid) RunRaw(ctx *mode.Context, argsText string) error {
+	if argsText == "" {
+		return ClearFilter{}.Execute(ctx)
+	}
+
+	filter, err := database.ParseRowFilter(argsText, ctx.DataGrid.AllHeaders())
+	if err != nil {
+		return err
+	}
+	applyFilter(ctx, filter)
+	return nil
+}
+
+// ClearFilter shows all rows of the grid again: `:nofilter`
+type ClearFilter struct{}
+
+func (ClearFilter) Run(ctx *mode.Context, args []string) error {
+	return ClearFilter{}.Execute(ctx)
+}
+
+func (ClearFilter) Execute(ctx *mode.Context) error {
+	if !ctx.DataGrid.IsFiltered() {
+		return nil
+	}
+	applyFilter(ctx, nil)
+	return nil
+}
+
+func applyFilter(ctx *mode.Context, filter *database.RowFilter) {
+	dg := ctx.DataGrid
+	dg.SetFilter(filter)
+	ctx.Cursor.Position.Row = 0
+	ctx.UpdateSpreadsheetPositionMax()
+	if filter == nil {
+		ctx.Cursor.Common.Logs.Log("Filter cleared")
+		return
+	}
+	ctx.Cursor.Common.Logs.Log(fmt.Sprintf("%d of %d rows", dg.Rows, dg.TotalRows()))
+}
+
+// QuickFilter keeps only rows equal (or not equal) to the current cell, combined with existing filter
+type QuickFilter struct {
+	Negate bool
+}
+
+func (q QuickFilter) Execute(ctx *mode.Context) error {
+	dg := ctx.DataGrid
+	pos := ctx.Cursor.Position
+	if pos.Row >= dg.Rows || pos.Col >= dg.Cols {
+		return nil
+	}
+
+	header := dg.Headers[pos.Col]
+	val := dg.Data[pos.Row][header]
+	var condition string
+	switch {
+	case val == nil && q.Negate:
+		condition = fmt.Sprintf("%s is not null", database.FilterColumn(header))
+	case val == nil:
+		condition = fmt.Sprintf("%s is null", database.FilterColumn(header))
+	case q.Negate:
+		condition = fmt.Sprintf("%s != %s", database.FilterColumn(header), database.FilterLiteral(val))
+	default:
+		condition = fmt.Sprintf("%s = %s", database.FilterColumn(header), database.FilterLiteral(val))
+	}
+	if dg.IsFiltered() {
+		condition = fmt.Sprintf("(%s) and %s", dg.Filter.Expression, condition)
+	}
+
+	filter, err := database.ParseRowFilter(condition, dg.AllHeaders())
+	if err != nil {
+		return err
+	}
+	applyFilter(ctx, filter)
+	return nil
+}
@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"github.com/quar15/qq-go/internal/cursor"
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+// UndoEditor reverts last change of editor, `u`. Insert session is reverted as a whole.
+type UndoEditor struct{}
+
+func (UndoEditor) Execute(ctx *mode.Context) error {
+	if ctx.Cursor.Type != cursor.TypeEditor || ctx.Cursor.Common.Mode != cursor.ModeNormal {
+		return nil
+	}
+	row, col, ok := ctx.EditorGrid.Undo(ctx.Cursor.Position.Row, ctx.Cursor.Position.Col)
+	if !ok {
+		ctx.Cursor.Common.Logs.Log("Already at oldest change")
+		return nil
+	}
+	ctx.Cursor.Position.Row, ctx.Cursor.Position.Col = row, col
+	ctx.UpdateCursorPositionMax()
+	return nil
+}
+
+// RedoEditor brings back change reverted with undo, Ctrl+R
+type RedoEditor struct{}
+
+func (RedoEditor) Execute(ctx *mode.Context) error {
+	if ctx.Cursor.Type != cursor.TypeEditor || ctx.Cursor.Common.Mode != cursor.ModeNormal {
+		return nil
+	}
+	row, col, ok := ctx.EditorGrid.Redo(ctx.Cursor.Position.Row, ctx.Cursor.Position.Col)
+	if !ok {
+		ctx.Cursor.Common.Logs.Log("Already at newest change")
+		return nil
+	}
+	ctx.Cursor.Position.Row, ctx.Cursor.Position.Col = row, col
+	ctx.UpdateCursorPositionMax()
+	return nil
+}
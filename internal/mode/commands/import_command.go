@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/quar15/qq-go/internal/cursor"
+	"github.com/quar15/qq-go/internal/database"
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+// ImportDataGrid pushes content of the spreadsheet (e.g. dropped CSV) into table: `:import <table> [--create]`
+type ImportDataGrid struct{}
+
+func (ImportDataGrid) Run(ctx *mode.Context, args []string) error {
+	if ctx.Cursor.Type != cursor.TypeSpreadsheet {
+		return errors.New("Import is available only from spreadsheet")
+	}
+
+	opts := database.ImportOptions{}
+	if idx := slices.Index(args, "--create"); idx >= 0 {
+		opts.CreateTable = true
+		args = slices.Delete(args, idx, idx+1)
+	}
+	if len(args) != 1 {
+		return errors.New("Usage: :import <table> [--create]")
+	}
+	opts.Table = args[0]
+
+	progress, err := ctx.ConnManager.ImportDataGrid(ctx.DataGrid, opts)
+	if err != nil {
+		return err
+	}
+
+	logs := &ctx.Cursor.Common.Logs
+	logs.Log(fmt.Sprintf("Importing %d row(s) into '%s'", ctx.DataGrid.Rows, opts.Table))
+	go func() {
+		for p := range progress {
+			switch {
+			case p.Err != nil:
+				slog.Error("Import failed", slog.String("table", opts.Table), slog.Any("error", p.Err))
+				logs.Log(fmt.Sprintf("ERR: Import into '%s' failed (%s)", opts.Table, p.Err))
+			case p.Done && p.ReportPath != "":
+				logs.Log(fmt.Sprintf("Imported %d/%d row(s) into '%s', %d failed (see '%s')", p.Imported, p.Total, opts.Table, p.Failed, p.ReportPath))
+			case p.Done:
+				logs.Log(fmt.Sprintf("Imported %d/%d row(s) into '%s'", p.Imported, p.Total, opts.Table))
+			default:
+				logs.Log(fmt.Sprintf("Importing into '%s' | %d/%d row(s), %d failed", opts.Table, p.Imported+p.Failed, p.Total, p.Failed))
+			}
+		}
+	}()
+
+	return nil
+}
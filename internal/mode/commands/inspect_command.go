@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/quar15/qq-go/internal/config"
+	"github.com/quar15/qq-go/internal/format"
+	"github.com/quar15/qq-go/internal/mode"
+	"golang.design/x/clipboard"
+)
+
+const hexDumpBytesPerLine int = 16
+
+// InspectCell shows full value of current cell in scrollable popup, `K` or Enter
+type InspectCell struct{}
+
+func (InspectCell) Execute(ctx *mode.Context) error {
+	dg := ctx.DataGrid
+	pos := ctx.Cursor.Position
+	if pos.Row >= dg.Rows || pos.Col >= dg.Cols {
+		return nil
+	}
+	header := dg.Headers[pos.Col]
+	val := dg.Data[pos.Row][header]
+
+	var typeName string
+	if int(pos.Col) < len(dg.ColumnTypes) {
+		typeName = dg.ColumnTypes[pos.Col]
+	}
+	title := fmt.Sprintf("%s, row %d", header, pos.Row+1)
+	if typeName != "" {
+		title = fmt.Sprintf("%s (%s), row %d", header, typeName, pos.Row+1)
+	}
+
+	var (
+		lines    []mode.PopupLine
+		copyText string
+		wrap     bool
+	)
+	if raw, ok := val.([]byte); ok && typeName != "json" && typeName != "jsonb" {
+		lines = hexDumpLines(raw)
+		copyText = fmt.Sprintf(`\x%x`, raw)
+		title = fmt.Sprintf("%s, %d byte(s)", title, len(raw))
+	} else if pretty, ok := prettyJSON(val); ok {
+		lines = jsonLines(pretty)
+		copyText = pretty
+		wrap = true
+	} else if val == nil {
+		lines = []mode.PopupLine{mode.PlainPopupLine("NULL", config.Get().Colors.Overlay0())}
+	} else {
+		copyText = format.GetValueAsString(val)
+		for _, line := range strings.Split(copyText, "\n") {
+			lines = append(lines, mode.PlainPopupLine(strings.ReplaceAll(line, "\t", "    "), config.Get().Colors.Text()))
+		}
+		wrap = true
+	}
+
+	ctx.Popup.Open(title, lines, "y: copy | q: close", map[rune]mode.Command{
+		'y': copyInspectedValue{text: copyText},
+	})
+	ctx.Popup.SetWrap(wrap)
+	return nil
+}
+
+type copyInspectedValue struct {
+	text string
+}
+
+func (c copyInspectedValue) Execute(ctx *mode.Context) error {
+	clipboard.Write(clipboard.FmtText, []byte(c.text))
+	slog.Debug("Copied inspected value to clipboard", slog.Int("length", len(c.text)))
+	ctx.Cursor.Common.Logs.Log("Copied value to clipboard")
+	return nil
+}
+
+// prettyJSON indents JSON value, text is treated as JSON only when it is valid object or array
+func prettyJSON(val any) (string, bool) {
+	var raw []byte
+	switch v := val.(type) {
+	case map[string]any, []any:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		raw = b
+	case string:
+		raw = []byte(strings.TrimSpace(v))
+	case []byte:
+		raw = bytes.TrimSpace(v)
+	case json.RawMessage:
+		raw = bytes.TrimSpace(v)
+	default:
+		return "", false
+	}
+	if len(raw) == 0 || (raw[0] != '{' && raw[0] != '[') || !json.Valid(raw) {
+		return "", false
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, raw, "", "  "); err != nil {
+		return "", false
+	}
+	return out.String(), true
+}
+
+// jsonLines colours indented JSON: keys, strings, numbers, literals and punctuation
+func jsonLines(pretty string) []mode.PopupLine {
+	colors := config.Get().Colors
+	var lines []mode.PopupLine
+	for _, text := range strings.Split(pretty, "\n") {
+		var line mode.PopupLine
+		for i := 0; i < len(text); {
+			start := i
+			var color rl.Color
+			switch c := text[i]; {
+			case c == '"':
+				i++
+				for i < len(text) && text[i] != '"' {
+					if text[i] == '\\' {
+						i++
+					}
+					i++
+				}
+				i = min(i+1, len(text))
+				color = colors.Green()
+				if strings.HasPrefix(strings.TrimLeft(text[i:], " "), ":") {
+					color = colors.Accent()
+				}
+			case c == '-' || (c >= '0' && c <= '9'):
+				for i < len(text) && strings.IndexByte("+-.eE0123456789", text[i]) >= 0 {
+					i++
+				}
+				color = colors.Peach()
+			case c >= 'a' && c <= 'z':
+				for i < len(text) && text[i] >= 'a' && text[i] <= 'z' {
+					i++
+				}
+				color = colors.Mauve()
+			default:
+				i++
+				color = colors.Overlay1()
+			}
+			line = append(line, mode.PopupSegment{Text: text[start:i], Color: color})
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// hexDumpLines renders bytes like `hexdump -C`: offset, hex bytes and printable characters
+func hexDumpLines(raw []byte) []mode.PopupLine {
+	colors := config.Get().Colors
+	lines := make([]mode.PopupLine, 0, len(raw)/hexDumpBytesPerLine+1)
+	for offset := 0; offset < len(raw); offset += hexDumpBytesPerLine {
+		chunk := raw[offset:min(offset+hexDumpBytesPerLine, len(raw))]
+
+		var hexPart, textPart strings.Builder
+		for i := range hexDumpBytesPerLine {
+			if i == hexDumpBytesPerLine/2 {
+				hexPart.WriteByte(' ')
+			}
+			if i >= len(chunk) {
+				hexPart.WriteString("   ")
+				continue
+			}
+			fmt.Fprintf(&hexPart, "%02x ", chunk[i])
+			if chunk[i] >= 32 && chunk[i] < 127 {
+				textPart.WriteByte(chunk[i])
+			} else {
+				textPart.WriteByte('.')
+			}
+		}
+
+		lines = append(lines, mode.PopupLine{
+			{Text: fmt.Sprintf("%08x  ", offset), Color: colors.Overlay0()},
+			{Text: hexPart.String(), Color: colors.Text()},
+			{Text: " |" + textPart.String() + "|", Color: colors.Overlay1()},
+		})
+	}
+	if len(lines) == 0 {
+		lines = append(lines, mode.PlainPopupLine("(empty)", colors.Overlay0()))
+	}
+	return lines
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+type Listen struct{}
+
+func (Listen) Run(ctx *mode.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("Usage: :listen <channel> [channel...]")
+	}
+
+	connName := ctx.ConnManager.GetCurrentConnectionName()
+	logs := &ctx.Cursor.Common.Logs
+	// Result is logged from listener goroutine, logs are sent over channel so it is safe
+	return ctx.ConnManager.Listen(args, func(channel string, err error) {
+		if err != nil {
+			logs.Log(fmt.Sprintf("ERR: LISTEN on '%s' failed: %s", channel, err))
+			return
+		}
+		logs.Log(fmt.Sprintf("Listening on '%s' via '%s'", channel, connName))
+	})
+}
+
+type Unlisten struct{}
+
+func (Unlisten) Run(ctx *mode.Context, args []string) error {
+	if err := ctx.ConnManager.Unlisten(args); err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		ctx.Cursor.Common.Logs.Log("Stopped listening on all channels")
+	} else {
+		ctx.Cursor.Common.Logs.Log(fmt.Sprintf("Stopped listening on '%s'", strings.Join(args, ", ")))
+	}
+	return nil
+}
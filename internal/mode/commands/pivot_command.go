@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/quar15/qq-go/internal/database"
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+var pivotAggregatePattern = regexp.MustCompile(`^(\w+)(?:\((.+)\))?$`)
+
+// PivotResult groups displayed rows and opens aggregated result in new tab:
+// `:pivot <row>[,<row>...] [<column>] [count|sum|avg|min|max(<value>)]`, e.g. `:pivot month region sum(amount)`.
+// Without aggregate rows of each group are counted.
+type PivotResult struct{}
+
+func (PivotResult) Run(ctx *mode.Context, args []string) error {
+	const usage string = "Usage: pivot <row>[,<row>...] [<column>] [count|sum|avg|min|max(<value>)]"
+	if len(args) == 0 || len(args) > 3 {
+		return errors.New(usage)
+	}
+
+	spec := database.PivotSpec{Rows: strings.Split(args[0], ","), Aggregate: database.PivotCount}
+	var hasAggregate bool
+	for _, arg := range args[1:] {
+		if match := pivotAggregatePattern.FindStringSubmatch(arg); match != nil && !hasAggregate {
+			if aggregate, ok := database.ParsePivotAggregate(match[1]); ok && (match[2] != "" || aggregate == database.PivotCount) {
+				spec.Aggregate, spec.Value = aggregate, match[2]
+				hasAggregate = true
+				continue
+			}
+		}
+		if spec.Column != "" || hasAggregate {
+			return errors.New(usage)
+		}
+		spec.Column = arg
+	}
+
+	dg := ctx.DataGrid
+	pivot, err := database.PivotGrid(dg, spec)
+	if err != nil {
+		return err
+	}
+	ctx.Results.OpenInNewTab(pivot, "pivot "+spec.Describe(), dg.ConnectionName)
+	ctx.Cursor.Common.Logs.Log(fmt.Sprintf("Pivot %s: %d group(s) of %d row(s)", spec.Describe(), pivot.Rows, dg.Rows))
+	return nil
+}
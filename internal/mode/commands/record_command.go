@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+// ToggleRecordView switches spreadsheet between rows and vertical view of current row, `zx` or `:x`.
+// `:x <text>` opens the view with fields whose name or value contains text.
+type ToggleRecordView struct{}
+
+func (ToggleRecordView) Run(ctx *mode.Context, args []string) error {
+	if len(args) == 0 {
+		return ToggleRecordView{}.Execute(ctx)
+	}
+	if !ctx.Record.IsVisible() {
+		ctx.Record.Toggle()
+	}
+	ctx.Record.Search = strings.Join(args, " ")
+
+	fields := ctx.Record.Fields(ctx.DataGrid, ctx.Cursor.Position.Row)
+	if len(fields) == 0 {
+		ctx.Cursor.Common.Logs.Log(fmt.Sprintf("No field of row %d matches '%s'", ctx.Cursor.Position.Row+1, ctx.Record.Search))
+		return nil
+	}
+	ctx.Cursor.Position.Col = fields[0]
+	ctx.Cursor.Common.Logs.Log(fmt.Sprintf("%d field(s) match '%s'", len(fields), ctx.Record.Search))
+	return nil
+}
+
+func (ToggleRecordView) Execute(ctx *mode.Context) error {
+	if ctx.Record.Toggle() {
+		ctx.Cursor.Common.Logs.Log("Record view on")
+	} else {
+		ctx.Cursor.Common.Logs.Log("Record view off")
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/quar15/qq-go/internal/cursor"
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+// SelectRegister waits for register name used by next yank or put, e.g. `"ay` or `"+p`
+type SelectRegister struct{}
+
+func (SelectRegister) Execute(ctx *mode.Context) error {
+	ctx.Cursor.Common.Registers.Awaiting = true
+	return nil
+}
+
+// PasteEditor puts register after cursor, `p`, or before it with Before, `P`.
+// Charwise text goes after character, linewise below line and blockwise as column after cursor.
+type PasteEditor struct {
+	Before bool
+}
+
+func (p PasteEditor) Execute(ctx *mode.Context) error {
+	if ctx.Cursor.Type != cursor.TypeEditor || ctx.Cursor.Common.Mode != cursor.ModeNormal {
+		return nil
+	}
+	name := ctx.Cursor.Common.Registers.TakeSelected()
+	reg, err := ctx.Cursor.Common.Registers.Get(name)
+	if err != nil {
+		ctx.Cursor.Common.Logs.Log(fmt.Sprintf("ERR: %s", err))
+		return err
+	}
+
+	ctx.SaveUndoState()
+	eg := ctx.EditorGrid
+	pos := &ctx.Cursor.Position
+	col := pos.Col
+	if !p.Before && eg.Cols[pos.Row] > 0 {
+		col++
+	}
+
+	switch reg.Kind {
+	case cursor.RegisterLinewise:
+		row := pos.Row
+		if !p.Before {
+			row++
+		}
+		eg.InsertLines(row, reg.Lines)
+		pos.Row, pos.Col = row, 0
+	case cursor.RegisterBlockwise:
+		eg.InsertBlock(pos.Row, col, reg.Lines)
+		pos.Col = col
+	default:
+		endRow, endCol := eg.InsertText(pos.Row, col, reg.Lines)
+		if len(reg.Lines) == 1 {
+			pos.Row, pos.Col = endRow, max(endCol-1, 0)
+		} else {
+			pos.Col = col
+		}
+	}
+
+	ctx.UpdateCursorPositionMax()
+	if len(reg.Lines) > 1 {
+		ctx.Cursor.Common.Logs.Log(fmt.Sprintf("%d more lines", len(reg.Lines)-1))
+	}
+	return nil
+}
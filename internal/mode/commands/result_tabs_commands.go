@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+// CycleResultTab switches to next (or previous with negative offset) result tab, `gt` and `gT`
+type CycleResultTab struct {
+	Offset int
+}
+
+func (c CycleResultTab) Execute(ctx *mode.Context) error {
+	switched, err := ctx.Results.Cycle(c.Offset)
+	if err != nil {
+		ctx.Cursor.Common.Logs.Log(err.Error())
+		return err
+	}
+	if switched {
+		logCurrentResultTab(ctx)
+	}
+	return nil
+}
+
+// PinResultTab toggles pin of current result, next execution opens new tab instead of replacing pinned one: `:pin`
+type PinResultTab struct{}
+
+func (PinResultTab) Run(ctx *mode.Context, args []string) error {
+	pinned, err := ctx.Results.TogglePin()
+	if err != nil {
+		return err
+	}
+	if pinned {
+		ctx.Cursor.Common.Logs.Log("Result pinned, next result opens in new tab")
+	} else {
+		ctx.Cursor.Common.Logs.Log("Result unpinned")
+	}
+	return nil
+}
+
+// CloseResultTab closes current result tab, or tab of given number: `:tabclose [<number>]`
+type CloseResultTab struct{}
+
+func (CloseResultTab) Run(ctx *mode.Context, args []string) error {
+	idx := ctx.Results.Current
+	if len(args) > 0 {
+		var number int
+		if _, err := fmt.Sscanf(args[0], "%d", &number); err != nil {
+			return fmt.Errorf("Invalid tab number '%s'", args[0])
+		}
+		idx = number - 1
+	}
+	if err := ctx.Results.Close(idx); err != nil {
+		return err
+	}
+	if ctx.Results.CurrentTab() == nil {
+		ctx.Cursor.Common.Logs.Log("All results closed")
+		return nil
+	}
+	logCurrentResultTab(ctx)
+	return nil
+}
+
+// SwitchResultTab activates tab of given number: `:tab <number>`
+type SwitchResultTab struct{}
+
+func (SwitchResultTab) Run(ctx *mode.Context, args []string) error {
+	if len(args) != 1 {
+		return errors.New("Usage: tab <number>")
+	}
+	var number int
+	if _, err := fmt.Sscanf(args[0], "%d", &number); err != nil || number < 1 || number > len(ctx.Results.Tabs) {
+		return fmt.Errorf("Invalid tab number '%s'", args[0])
+	}
+	switched, err := ctx.Results.Switch(number - 1)
+	if err != nil {
+		return err
+	}
+	if switched {
+		logCurrentResultTab(ctx)
+	}
+	return nil
+}
+
+func logCurrentResultTab(ctx *mode.Context) {
+	ctx.Cursor.Common.Logs.Log(fmt.Sprintf("Result %d/%d: %s", ctx.Results.Current+1, len(ctx.Results.Tabs), ctx.Results.CurrentTab().Title()))
+}
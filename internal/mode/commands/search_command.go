@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+// StartSearch opens command line for search pattern, `/` searches forward and `?` backward.
+// Pattern is plain text, `\c` ignores case, `\v` makes it regular expression and `\%c` limits it to current column.
+type StartSearch struct {
+	Backward bool
+}
+
+func (s StartSearch) Execute(ctx *mode.Context) error {
+	prefix := '/'
+	if s.Backward {
+		prefix = '?'
+	}
+	ctx.Cursor.EnterCommandLine(prefix)
+	return nil
+}
+
+// SearchNext jumps to next match of last search, `n`, or previous one with reverse, `N`
+type SearchNext struct {
+	Reverse bool
+}
+
+func (s SearchNext) Execute(ctx *mode.Context) error {
+	mode.JumpToMatch(ctx, s.Reverse)
+	return nil
+}
+
+// ClearSearch stops highlighting matches of last search until `n` is used again: `:noh`
+type ClearSearch struct{}
+
+func (ClearSearch) Run(ctx *mode.Context, args []string) error {
+	ctx.Search.Clear()
+	return nil
+}
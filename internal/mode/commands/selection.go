@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"github.com/quar15/qq-go/internal/cursor"
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+// selectedGridData returns headers and row values of current spreadsheet selection.
+// Outside of visual modes whole grid is returned. In VISUAL mode cells outside of selection are nil.
+func selectedGridData(ctx *mode.Context) (headers []string, rows [][]any) {
+	c := ctx.Cursor
+	dg := ctx.DataGrid
+
+	startRow, endRow, startCol, endCol, ok := selectionBounds(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	headers = dg.Headers[startCol : endCol+1]
+	rows = make([][]any, 0, endRow-startRow+1)
+	for row := startRow; row <= endRow; row++ {
+		values := make([]any, 0, len(headers))
+		for col := startCol; col <= endCol; col++ {
+			if c.Common.Mode == cursor.ModeVisual && !c.IsSelected(col, row) {
+				values = append(values, nil)
+				continue
+			}
+			values = append(values, dg.Data[row][dg.Headers[col]])
+		}
+		rows = append(rows, values)
+	}
+
+	return headers, rows
+}
+
+// selectedRowsAllColumns returns every column of rows spanned by current selection, including hidden ones, in original order
+func selectedRowsAllColumns(ctx *mode.Context) (headers []string, rows [][]any) {
+	dg := ctx.DataGrid
+
+	startRow, endRow, _, _, ok := selectionBounds(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	headers = dg.AllHeaders()
+	rows = make([][]any, 0, endRow-startRow+1)
+	for row := startRow; row <= endRow; row++ {
+		values := make([]any, 0, len(headers))
+		for _, header := range headers {
+			values = append(values, dg.Data[row][header])
+		}
+		rows = append(rows, values)
+	}
+
+	return headers, rows
+}
+
+// selectionBounds returns rows and columns spanned by current spreadsheet selection (whole grid outside of visual modes)
+func selectionBounds(ctx *mode.Context) (startRow, endRow, startCol, endCol int32, ok bool) {
+	c := ctx.Cursor
+	dg := ctx.DataGrid
+
+	startRow, endRow = 0, dg.Rows-1
+	startCol, endCol = 0, dg.Cols-1
+	switch c.Common.Mode {
+	case cursor.ModeVisual:
+		startRow, endRow = c.Position.SelectStartRow, c.Position.SelectEndRow
+		if startRow == endRow {
+			startCol, endCol = c.Position.SelectStartCol, c.Position.SelectEndCol
+		}
+	case cursor.ModeVLine:
+		startRow, endRow = c.Position.SelectStartRow, c.Position.SelectEndRow
+	case cursor.ModeVBlock:
+		startRow, endRow = c.Position.SelectStartRow, c.Position.SelectEndRow
+		startCol, endCol = c.Position.SelectStartCol, c.Position.SelectEndCol
+	}
+	endRow = min(endRow, dg.Rows-1)
+	endCol = min(endCol, dg.Cols-1)
+	return startRow, endRow, startCol, endCol, startRow <= endRow && startCol <= endCol
+}
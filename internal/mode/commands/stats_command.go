@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/quar15/qq-go/internal/config"
+	"github.com/quar15/qq-go/internal/database"
+	"github.com/quar15/qq-go/internal/format"
+	"github.com/quar15/qq-go/internal/mode"
+)
+
+const statsTopValues int = 5
+const statsMaxValueLength int = 60
+
+// ShowColumnStats shows profile of selected columns (or all columns of the grid): `:stats`
+type ShowColumnStats struct{}
+
+func (ShowColumnStats) Run(ctx *mode.Context, args []string) error {
+	dg := ctx.DataGrid
+	startRow, endRow, startCol, endCol, ok := selectionBounds(ctx)
+	if !ok {
+		return errors.New("Nothing to profile")
+	}
+
+	colors := config.Get().Colors
+	var lines []mode.PopupLine
+	for col := startCol; col <= endCol; col++ {
+		header := dg.Headers[col]
+		values := make([]any, 0, endRow-startRow+1)
+		for row := startRow; row <= endRow; row++ {
+			values = append(values, dg.Data[row][header])
+		}
+		var typeName string
+		if int(col) < len(dg.ColumnTypes) {
+			typeName = dg.ColumnTypes[col]
+		}
+
+		p := database.ProfileColumn(header, typeName, values, statsTopValues)
+		title := mode.PopupLine{{Text: p.Header, Color: colors.Accent()}}
+		if p.Type != "" {
+			title = append(title, mode.PopupSegment{Text: " " + p.Type, Color: colors.Overlay0()})
+		}
+		lines = append(lines,
+			title,
+			statsLine("rows", fmt.Sprintf("%d", p.Rows)),
+			statsLine("nulls", fmt.Sprintf("%d (%.1f%%)", p.Nulls, p.NullRatio()*100)),
+			statsLine("distinct", fmt.Sprintf("%d", p.Distinct)),
+			statsLine("length", fmt.Sprintf("%d - %d", p.MinLength, p.MaxLength)),
+			statsLine("min", truncateValue(format.GetValueAsString(p.Min))),
+			statsLine("max", truncateValue(format.GetValueAsString(p.Max))),
+		)
+		for i, top := range p.TopValues {
+			label := ""
+			if i == 0 {
+				label = "top"
+			}
+			lines = append(lines, statsLine(label, fmt.Sprintf("%6d  %s", top.Count, truncateValue(top.Value))))
+		}
+		lines = append(lines, mode.PopupLine{})
+	}
+
+	ctx.Popup.Open(
+		fmt.Sprintf("Profile of %d column(s), %d row(s)", endCol-startCol+1, endRow-startRow+1),
+		lines,
+		"q: close",
+		nil,
+	)
+	return nil
+}
+
+func statsLine(label string, value string) mode.PopupLine {
+	return mode.PopupLine{
+		{Text: fmt.Sprintf("  %-10s", label), Color: config.Get().Colors.Overlay1()},
+		{Text: value, Color: config.Get().Colors.Text()},
+	}
+}
+
+func truncateValue(value string) string {
+	value = strings.ReplaceAll(value, "\n", " ")
+	if runes := []rune(value); len(runes) > statsMaxValueLength {
+		return string(runes[:statsMaxValueLength]) + "..."
+	}
+	return value
+}
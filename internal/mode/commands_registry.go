@@ -6,13 +6,41 @@ type Command interface {
 	Execute(ctx *Context) error
 }
 
+// ExCommand is a command invoked by name from the command line (e.g. `:listen jobs`)
+type ExCommand interface {
+	Run(ctx *Context, args []string) error
+}
+
+// RawExCommand is an ExCommand which receives its arguments exactly as typed (e.g. `:filter name = 'a  b'`)
+type RawExCommand interface {
+	ExCommand
+	RunRaw(ctx *Context, argsText string) error
+}
+
+type commandNode struct {
+	children map[motion.Key]*commandNode
+	cmd      Command
+}
+
+// pendingSequence is returned by lookup while multi-key sequence is not finished yet
+type pendingSequence struct{}
+
+func (pendingSequence) Execute(ctx *Context) error {
+	return nil
+}
+
 type CommandRegistry struct {
-	bindings map[motion.Key]Command
+	bindings   map[motion.Key]Command
+	sequences  *commandNode
+	pending    *commandNode
+	exCommands map[string]ExCommand
 }
 
 func NewCommandRegistry() *CommandRegistry {
 	return &CommandRegistry{
-		bindings: make(map[motion.Key]Command),
+		bindings:   make(map[motion.Key]Command),
+		sequences:  &commandNode{children: map[motion.Key]*commandNode{}},
+		exCommands: make(map[string]ExCommand),
 	}
 }
 
@@ -20,7 +48,72 @@ func (r *CommandRegistry) Bind(k motion.Key, cmd Command) {
 	r.bindings[k] = cmd
 }
 
+// BindSequence binds command to sequence of keys (e.g. `dd`), sequences take precedence over motions
+func (r *CommandRegistry) BindSequence(keys []motion.Key, cmd Command) {
+	node := r.sequences
+	for _, k := range keys {
+		next, ok := node.children[k]
+		if !ok {
+			next = &commandNode{children: map[motion.Key]*commandNode{}}
+			node.children[k] = next
+		}
+		node = next
+	}
+	node.cmd = cmd
+}
+
 func (r *CommandRegistry) Lookup(k motion.Key) (Command, bool) {
-	cmd, ok := r.bindings[k]
+	var wasPending bool = r.pending != nil
+	if r.pending != nil {
+		node, ok := r.pending.children[k]
+		r.pending = nil
+		if ok {
+			return r.advance(node), true
+		}
+	}
+
+	if cmd, ok := r.bindings[k]; ok {
+		return cmd, ok
+	}
+	// Key which broke pending sequence is left to motions, so `gg` works while `gt` is bound
+	if node, ok := r.sequences.children[k]; ok && !wasPending {
+		return r.advance(node), true
+	}
+	return nil, false
+}
+
+// lookupCommand finds command bound to key, keys continuing started motion or operator are left to parser (e.g. `u` of `gu`)
+func lookupCommand(ctx *Context, k motion.Key) (Command, bool) {
+	if ctx.Parser.InProgress() && !ctx.Commands.IsPending() {
+		return nil, false
+	}
+	return ctx.Commands.Lookup(k)
+}
+
+// IsPending reports if registry waits for next key of sequence
+func (r *CommandRegistry) IsPending() bool {
+	return r.pending != nil
+}
+
+// IsPendingSequence reports if command is placeholder returned while sequence is not finished
+func IsPendingSequence(cmd Command) bool {
+	_, ok := cmd.(pendingSequence)
+	return ok
+}
+
+func (r *CommandRegistry) advance(node *commandNode) Command {
+	if node.cmd != nil && len(node.children) == 0 {
+		return node.cmd
+	}
+	r.pending = node
+	return pendingSequence{}
+}
+
+func (r *CommandRegistry) BindEx(name string, cmd ExCommand) {
+	r.exCommands[name] = cmd
+}
+
+func (r *CommandRegistry) LookupEx(name string) (ExCommand, bool) {
+	cmd, ok := r.exCommands[name]
 	return cmd, ok
 }
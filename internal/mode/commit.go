@@ -0,0 +1,80 @@
+package mode
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/quar15/qq-go/internal/database"
+)
+
+// pendingCommit is transaction of tab changes running in background, its result is applied on main loop
+type pendingCommit struct {
+	statements []database.Statement
+	results    <-chan database.ExecResult
+}
+
+// IsCommitting reports if changes of tab are being committed
+func (t *ResultTab) IsCommitting() bool {
+	return t.commit != nil
+}
+
+// StartCommit binds running transaction to current tab, so result lands on its grid even after tabs changed
+func (rt *ResultTabs) StartCommit(statements []database.Statement, results <-chan database.ExecResult) error {
+	tab := rt.CurrentTab()
+	if tab == nil {
+		return errors.New("No result to commit")
+	}
+	if tab.IsCommitting() {
+		return errors.New("Commit of this result is already running")
+	}
+	tab.commit = &pendingCommit{statements: statements, results: results}
+	return nil
+}
+
+// HandleCommitResults applies finished commits to grids of their tabs, called from main loop like query results
+func (ctx *Context) HandleCommitResults() {
+	logs := &ctx.Cursor.Common.Logs
+	for idx, tab := range ctx.Results.Tabs {
+		if tab.commit == nil {
+			continue
+		}
+		var res database.ExecResult
+		select {
+		case r, ok := <-tab.commit.results:
+			if !ok {
+				r = database.ExecResult{Err: errors.New("Commit ended without result"), FailedStatement: -1}
+			}
+			res = r
+		default:
+			continue
+		}
+		statements := tab.commit.statements
+		tab.commit = nil
+		dg := ctx.Results.Grid(idx)
+
+		if res.Err != nil {
+			slog.Error("Failed to commit changes", slog.Int("statement", res.FailedStatement), slog.Any("error", res.Err))
+			if res.FailedStatement < 0 {
+				logs.Log(fmt.Sprintf("ERR: Rolled back (%s)", res.Err))
+				continue
+			}
+			// Point at the row which caused constraint violation
+			key := statements[res.FailedStatement].RowKey
+			dg.Changes.SetFailedRow(key, res.Err)
+			if row := dg.FindRowByKey(key); row >= 0 {
+				logs.Log(fmt.Sprintf("ERR: Rolled back, row %d failed (%s)", row+1, res.Err))
+			} else {
+				logs.Log(fmt.Sprintf("ERR: Rolled back, statement %d failed (%s)", res.FailedStatement+1, res.Err))
+			}
+			continue
+		}
+
+		dg.ApplyCommitted()
+		if idx == ctx.Results.Current {
+			// Deleted rows are gone from grid now
+			ctx.UpdateSpreadsheetPositionMax()
+		}
+		logs.Log(fmt.Sprintf("Committed %d statement(s)", len(statements)))
+	}
+}
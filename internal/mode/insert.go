@@ -11,6 +11,11 @@ import (
 type InsertMode struct{}
 
 func (InsertMode) Handle(ctx *Context, k motion.Key) {
+	if ctx.Cursor.Type == cursor.TypeSpreadsheet {
+		handleCellInsert(ctx, k)
+		return
+	}
+
 	row := ctx.Cursor.Position.Row
 	col := ctx.Cursor.Position.Col
 
@@ -21,6 +26,10 @@ func (InsertMode) Handle(ctx *Context, k motion.Key) {
 
 		return
 	}
+	if k == motion.CtrlR {
+		ctx.Cursor.Common.Registers.Awaiting = true
+		return
+	}
 
 	switch k.Rune {
 	case rl.KeyEnter:
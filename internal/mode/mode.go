@@ -21,9 +21,23 @@ type Context struct {
 	WindowManager *WindowManager
 	EditorGrid    *editor.Grid
 	DataGrid      *database.DataGrid
+	Popup         *Popup
+	Results       *ResultTabs
+	Chart         *ChartView
+	Record        *RecordView
+	Search        *Search
 }
 
 func HandleKey(ctx *Context, k motion.Key) {
+	if ctx.Popup.IsVisible() {
+		PopupMode{}.Handle(ctx, k)
+		return
+	}
+	if ctx.Cursor.Common.Registers.Awaiting {
+		handleRegisterName(ctx, k)
+		return
+	}
+
 	switch ctx.Cursor.Common.Mode {
 	case cursor.ModeNormal:
 		NormalMode{}.Handle(ctx, k)
@@ -51,11 +65,27 @@ func HandleKey(ctx *Context, k motion.Key) {
 	}
 }
 
+func (ctx *Context) UpdateSpreadsheetPositionMax() {
+	ctx.Cursor.Position.MaxCol = max(0, ctx.DataGrid.Cols-1)
+	ctx.Cursor.Position.MaxRow = max(0, ctx.DataGrid.Rows-1)
+	ctx.Cursor.Position.Row = min(ctx.Cursor.Position.Row, ctx.Cursor.Position.MaxRow)
+	ctx.Cursor.Position.Col = min(ctx.Cursor.Position.Col, ctx.Cursor.Position.MaxCol)
+}
+
 func (ctx *Context) UpdateCursorPositionMax() {
 	ctx.Cursor.Position.UpdateMax(
 		max(0, ctx.EditorGrid.Cols[ctx.Cursor.Position.Row]-1),
 		max(0, ctx.EditorGrid.Rows-1),
 		ctx.EditorGrid.Cols,
+		ctx.EditorGrid.Text,
 	)
 	//slog.Debug("Cursor max positions updated", slog.Any("ctx.Cursor.Position", ctx.Cursor.Position))
 }
+
+// SaveUndoState starts new undo step of editor, called before change or when insert session begins
+func (ctx *Context) SaveUndoState() {
+	if ctx.EditorGrid == nil {
+		return
+	}
+	ctx.EditorGrid.SaveUndoState(ctx.Cursor.Position.Row, ctx.Cursor.Position.Col)
+}
@@ -12,26 +12,40 @@ import (
 type NormalMode struct{}
 
 func (NormalMode) Handle(ctx *Context, k motion.Key) {
+	if ctx.Parser.InProgress() {
+		// Keys after operator or sequence prefix belong to them, e.g. `i` of `di` is not insert
+		dispatchNormalKey(ctx, k)
+		return
+	}
+	if ctx.Cursor.Type == cursor.TypeSpreadsheet && handleSpreadsheetNormalKey(ctx, k) {
+		return
+	}
+
 	switch k.Rune {
 	case rl.KeyEscape, rl.KeyCapsLock:
 		ctx.Parser.Reset()
 	case 'i':
+		ctx.SaveUndoState()
 		ctx.Cursor.TransitionMode(cursor.ModeInsert)
 	case 'a':
+		ctx.SaveUndoState()
 		if ctx.Cursor.Position.Col < ctx.EditorGrid.Cols[ctx.Cursor.Position.Row] {
 			ctx.Cursor.Position.Col++
 		}
 		ctx.Cursor.TransitionMode(cursor.ModeInsert)
 		ctx.UpdateCursorPositionMax()
 	case 'A':
+		ctx.SaveUndoState()
 		ctx.Cursor.Position.Col = ctx.EditorGrid.Cols[ctx.Cursor.Position.Row]
 		ctx.Cursor.TransitionMode(cursor.ModeInsert)
 		ctx.UpdateCursorPositionMax()
 	case 'O':
+		ctx.SaveUndoState()
 		ctx.Cursor.Position.Row, ctx.Cursor.Position.Col = ctx.EditorGrid.InsertEmptyLineAbove(ctx.Cursor.Position.Row)
 		ctx.Cursor.TransitionMode(cursor.ModeInsert)
 		ctx.UpdateCursorPositionMax()
 	case 'o':
+		ctx.SaveUndoState()
 		ctx.Cursor.Position.Row, ctx.Cursor.Position.Col = ctx.EditorGrid.InsertEmptyLineBelow(ctx.Cursor.Position.Row)
 		ctx.Cursor.TransitionMode(cursor.ModeInsert)
 		ctx.UpdateCursorPositionMax()
@@ -50,23 +64,39 @@ func (NormalMode) Handle(ctx *Context, k motion.Key) {
 		ctx.Parser.Reset()
 		return
 	case ':':
-		ctx.Cursor.TransitionMode(cursor.ModeCommand)
+		ctx.Cursor.EnterCommandMode()
 		ctx.Parser.Reset()
 		return
 	}
 
-	if cmd, ok := ctx.Commands.Lookup(k); ok {
+	dispatchNormalKey(ctx, k)
+}
+
+// dispatchNormalKey executes bound command or feeds parser with key of motion or operator
+func dispatchNormalKey(ctx *Context, k motion.Key) {
+	if cmd, ok := lookupCommand(ctx, k); ok {
+		if IsPendingSequence(cmd) {
+			// Motions can share prefix with sequence, so parser sees the key too
+			ctx.Parser.Feed(k)
+			return
+		}
+		ctx.Parser.Reset()
 		slog.Debug("Normal Mode | Trying to execute command", slog.String("cmd", fmt.Sprintf("%T", cmd)))
-		err := cmd.Execute(ctx)
-		if err != nil {
+		if err := cmd.Execute(ctx); err != nil {
 			slog.Error("Normal Mode | Failed to execute command", slog.String("cmd", fmt.Sprintf("%T", cmd)))
 		}
 		return
 	}
 
 	res := ctx.Parser.Feed(k)
-	if res.Done && res.Valid {
-		slog.Debug("Normal Mode | Applying motion", slog.String("res.Motion", fmt.Sprintf("%T", res.Motion)), slog.Int("res.Count", res.Count))
-		ctx.Cursor.Position = res.Motion.Apply(ctx.Cursor.Position, res.Count, res.HasCount)
+	if !res.Done || !res.Valid {
+		return
+	}
+	if res.Operator != motion.OperatorNone {
+		slog.Debug("Normal Mode | Applying operator", slog.Any("res.Operator", res.Operator), slog.String("res.Motion", fmt.Sprintf("%T", res.Motion)), slog.Int("res.Count", res.Count))
+		applyMotionOperator(ctx, res)
+		return
 	}
+	slog.Debug("Normal Mode | Applying motion", slog.String("res.Motion", fmt.Sprintf("%T", res.Motion)), slog.Int("res.Count", res.Count))
+	ctx.Cursor.Position = res.Motion.Apply(ctx.Cursor.Position, res.Count, res.HasCount)
 }
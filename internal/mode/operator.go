@@ -0,0 +1,298 @@
+package mode
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/quar15/qq-go/internal/config"
+	"github.com/quar15/qq-go/internal/cursor"
+	"github.com/quar15/qq-go/internal/editor"
+	"github.com/quar15/qq-go/internal/export"
+	"github.com/quar15/qq-go/internal/motion"
+)
+
+// applyMotionOperator applies operator of parsed `[count]operator[count]motion` to text between cursor and motion target
+func applyMotionOperator(ctx *Context, res motion.Result) {
+	from := ctx.Cursor.Position
+	m := res.Motion
+	word, isWord := m.(motion.MoveWordForward)
+	if isWord && res.Operator == motion.OperatorChange && ctx.Cursor.Type == cursor.TypeEditor && !isBlankAt(ctx.EditorGrid, from.Row, from.Col) {
+		// Like in vim `cw` changes only to end of word, as `ce`
+		m, isWord = motion.MoveWordEnd{BigWord: word.BigWord}, false
+	}
+
+	to, kind, ok := motion.Target(m, from, res.Count, res.HasCount)
+	if !ok {
+		return
+	}
+
+	switch ctx.Cursor.Type {
+	case cursor.TypeEditor:
+		eg := ctx.EditorGrid
+		r, ok := motionRange(eg, from, to, kind)
+		if !ok {
+			return
+		}
+		if isWord && to.Row > from.Row && to.Col <= eg.Indentation(to.Row) {
+			// Word at end of line is operated without line break after it, e.g. `dw` on last word
+			r.EndRow = to.Row - 1
+			r.EndCol = eg.Cols[r.EndRow] - 1
+		}
+		operateEditor(ctx, res.Operator, r)
+	case cursor.TypeSpreadsheet:
+		startRow, endRow := min(from.Row, to.Row), max(from.Row, to.Row)
+		startCol, endCol := min(from.Col, to.Col), max(from.Col, to.Col)
+		if kind == motion.MotionExclusive {
+			// Cell under target is left out whatever the direction, so `yl` copies current cell and `yh` the one left of it.
+			// Motion which could not move works on nothing, except moving right in last column (like `l` at end of line in vim).
+			switch {
+			case from.Col != to.Col:
+				endCol--
+			case !movesRight(m):
+				return
+			}
+		}
+		operateSpreadsheet(ctx, res.Operator, startRow, endRow, startCol, endCol, kind == motion.MotionLinewise)
+	}
+}
+
+// applySelectionOperator applies operator to selection of visual modes and leaves them
+func applySelectionOperator(ctx *Context, op motion.Operator) {
+	switch ctx.Cursor.Type {
+	case cursor.TypeEditor:
+		r, ok := SelectionRange(ctx)
+		if !ok {
+			return
+		}
+		ctx.Cursor.TransitionMode(cursor.ModeNormal)
+		operateEditor(ctx, op, r)
+	case cursor.TypeSpreadsheet:
+		pos := ctx.Cursor.Position
+		lines := ctx.Cursor.Common.Mode == cursor.ModeVLine
+		ctx.Cursor.TransitionMode(cursor.ModeNormal)
+		operateSpreadsheet(ctx, op, pos.SelectStartRow, pos.SelectEndRow, pos.SelectStartCol, pos.SelectEndCol, lines)
+	}
+}
+
+// SelectionRange returns editor text selected in visual modes, outside of them current line
+func SelectionRange(ctx *Context) (editor.Range, bool) {
+	eg := ctx.EditorGrid
+	pos := ctx.Cursor.Position
+	if pos.Row >= eg.Rows {
+		return editor.Range{}, false
+	}
+
+	switch ctx.Cursor.Common.Mode {
+	case cursor.ModeVisual:
+		startRow, startCol := pos.SelectAnchorRow, pos.SelectAnchorCol
+		endRow, endCol := pos.Row, pos.Col
+		if startRow > endRow || (startRow == endRow && startCol > endCol) {
+			startRow, endRow = endRow, startRow
+			startCol, endCol = endCol, startCol
+		}
+		return editor.Range{StartRow: startRow, StartCol: startCol, EndRow: endRow, EndCol: endCol, Kind: editor.RangeCharwise}, true
+	case cursor.ModeVLine:
+		return editor.Range{StartRow: pos.SelectStartRow, EndRow: pos.SelectEndRow, Kind: editor.RangeLinewise}, true
+	case cursor.ModeVBlock:
+		return editor.Range{
+			StartRow: pos.SelectStartRow,
+			StartCol: pos.SelectStartCol,
+			EndRow:   pos.SelectEndRow,
+			EndCol:   pos.SelectEndCol,
+			Kind:     editor.RangeBlockwise,
+		}, true
+	default:
+		return editor.Range{StartRow: pos.Row, EndRow: pos.Row, EndCol: eg.Cols[pos.Row] - 1, Kind: editor.RangeCharwise}, true
+	}
+}
+
+// RangeRegister returns text of range as register of matching kind
+func RangeRegister(eg *editor.Grid, r editor.Range) cursor.Register {
+	var kind cursor.RegisterKind
+	switch r.Kind {
+	case editor.RangeLinewise:
+		kind = cursor.RegisterLinewise
+	case editor.RangeBlockwise:
+		kind = cursor.RegisterBlockwise
+	default:
+		kind = cursor.RegisterCharwise
+	}
+	return cursor.Register{Lines: eg.RangeText(r), Kind: kind}
+}
+
+// motionRange turns cursor movement into range, exclusive motion ending at start of line stops at end of previous one
+func motionRange(eg *editor.Grid, from motion.CursorPosition, to motion.CursorPosition, kind motion.MotionKind) (editor.Range, bool) {
+	if to.Row < from.Row || (to.Row == from.Row && to.Col < from.Col) {
+		from, to = to, from
+	}
+	r := editor.Range{StartRow: from.Row, StartCol: from.Col, EndRow: to.Row, EndCol: to.Col, Kind: editor.RangeCharwise}
+
+	switch kind {
+	case motion.MotionLinewise:
+		r.Kind = editor.RangeLinewise
+	case motion.MotionExclusive:
+		if from.Row == to.Row && from.Col == to.Col {
+			return editor.Range{}, false
+		}
+		r.EndCol--
+		if r.EndCol < 0 && r.EndRow > r.StartRow {
+			r.EndRow--
+			r.EndCol = eg.Cols[r.EndRow] - 1
+		}
+	}
+	return r, true
+}
+
+func operateEditor(ctx *Context, op motion.Operator, r editor.Range) {
+	eg := ctx.EditorGrid
+	pos := &ctx.Cursor.Position
+	lines := r.EndRow - r.StartRow + 1
+
+	switch op {
+	case motion.OperatorYank:
+		if err := YankToRegister(ctx, RangeRegister(eg, r)); err != nil {
+			ctx.Cursor.Common.Logs.Log(fmt.Sprintf("ERR: %s", err))
+			return
+		}
+		pos.Row = r.StartRow
+		if r.Kind != editor.RangeLinewise {
+			pos.Col = r.StartCol
+		}
+		if lines > 1 {
+			ctx.Cursor.Common.Logs.Log(fmt.Sprintf("%d lines yanked", lines))
+		}
+
+	case motion.OperatorDelete, motion.OperatorChange:
+		ctx.SaveUndoState()
+		if err := YankToRegister(ctx, RangeRegister(eg, r)); err != nil {
+			ctx.Cursor.Common.Logs.Log(fmt.Sprintf("ERR: %s", err))
+			return
+		}
+		if op == motion.OperatorChange && r.Kind == editor.RangeLinewise {
+			// Changed lines are replaced with one empty line to type in
+			eg.ReplaceLines(r.StartRow, r.EndRow, []string{""})
+		} else {
+			eg.DeleteRange(r)
+		}
+		pos.Row, pos.Col = min(r.StartRow, eg.Rows-1), r.StartCol
+		if r.Kind == editor.RangeLinewise {
+			pos.Col = 0
+		}
+		if op == motion.OperatorChange {
+			ctx.Cursor.TransitionMode(cursor.ModeInsert)
+			break
+		}
+		pos.Col = min(pos.Col, max(eg.Cols[pos.Row]-1, 0))
+		if lines > 2 {
+			ctx.Cursor.Common.Logs.Log(fmt.Sprintf("%d fewer lines", lines))
+		}
+
+	case motion.OperatorIndent, motion.OperatorOutdent:
+		ctx.SaveUndoState()
+		width := config.Get().Editor.WithDefaults().ShiftWidth
+		sign := ">"
+		if op == motion.OperatorOutdent {
+			width, sign = -width, "<"
+		}
+		eg.ShiftLines(r.StartRow, r.EndRow, width)
+		pos.Row = r.StartRow
+		pos.Col = eg.Indentation(pos.Row)
+		if lines > 2 {
+			ctx.Cursor.Common.Logs.Log(fmt.Sprintf("%d lines %sed 1 time", lines, sign))
+		}
+
+	case motion.OperatorLower, motion.OperatorUpper, motion.OperatorToggleCase:
+		ctx.SaveUndoState()
+		eg.MapRange(r, caseMapper(op))
+		pos.Row = r.StartRow
+		if r.Kind != editor.RangeLinewise {
+			pos.Col = r.StartCol
+		}
+	}
+
+	ctx.UpdateCursorPositionMax()
+}
+
+func movesRight(m motion.Motion) bool {
+	switch m.(type) {
+	case motion.MoveRight, motion.MoveWordForward:
+		return true
+	}
+	return false
+}
+
+func isBlankAt(eg *editor.Grid, row int32, col int32) bool {
+	line := eg.Text[row]
+	return int(col) >= len(line) || line[col] == ' ' || line[col] == '\t'
+}
+
+func caseMapper(op motion.Operator) func(string) string {
+	switch op {
+	case motion.OperatorLower:
+		return strings.ToLower
+	case motion.OperatorUpper:
+		return strings.ToUpper
+	default:
+		return func(s string) string {
+			return strings.Map(func(r rune) rune {
+				if unicode.IsUpper(r) {
+					return unicode.ToLower(r)
+				}
+				return unicode.ToUpper(r)
+			}, s)
+		}
+	}
+}
+
+// operateSpreadsheet yanks rows, or cells of rows between columns, as TSV. Without `"` register clipboard is used,
+// since spreadsheet has nothing to put text into.
+func operateSpreadsheet(ctx *Context, op motion.Operator, startRow, endRow, startCol, endCol int32, wholeRows bool) {
+	dg := ctx.DataGrid
+	if op != motion.OperatorYank || dg.Rows == 0 || dg.Cols == 0 {
+		return
+	}
+	endRow, endCol = min(endRow, dg.Rows-1), min(endCol, dg.Cols-1)
+	if wholeRows {
+		startCol, endCol = 0, dg.Cols-1
+	}
+
+	headers := dg.Headers[startCol : endCol+1]
+	rows := make([][]any, 0, endRow-startRow+1)
+	for row := startRow; row <= endRow; row++ {
+		values := make([]any, 0, len(headers))
+		for _, header := range headers {
+			values = append(values, dg.Data[row][header])
+		}
+		rows = append(rows, values)
+	}
+	var sb strings.Builder
+	if err := export.Write(&sb, export.FormatTSV, headers, rows, export.Options{}); err != nil {
+		ctx.Cursor.Common.Logs.Log(fmt.Sprintf("ERR: %s", err))
+		return
+	}
+
+	reg := cursor.RegisterFromText(sb.String())
+	if !wholeRows {
+		reg.Kind = cursor.RegisterCharwise
+		if len(reg.Lines) > 1 {
+			reg.Kind = cursor.RegisterBlockwise
+		}
+	}
+	registers := &ctx.Cursor.Common.Registers
+	if registers.Selected == 0 {
+		registers.Selected = cursor.ClipboardRegister
+	}
+	if err := YankToRegister(ctx, reg); err != nil {
+		ctx.Cursor.Common.Logs.Log(fmt.Sprintf("ERR: %s", err))
+		return
+	}
+
+	ctx.Cursor.Position.Row = startRow
+	if wholeRows {
+		ctx.Cursor.Common.Logs.Log(fmt.Sprintf("Yanked %d row(s)", len(rows)))
+	} else {
+		ctx.Cursor.Position.Col = startCol
+		ctx.Cursor.Common.Logs.Log(fmt.Sprintf("Yanked %d cell(s)", len(rows)*len(headers)))
+	}
+}
@@ -0,0 +1,147 @@
+package mode
+
+import (
+	"fmt"
+	"log/slog"
+	"unicode/utf8"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/quar15/qq-go/internal/motion"
+)
+
+type PopupSegment struct {
+	Text  string
+	Color rl.Color
+}
+
+type PopupLine []PopupSegment
+
+// Popup is scrollable overlay shared by all windows (review of changes, inspectors, profiles...)
+type Popup struct {
+	Title     string
+	Footer    string
+	Lines     []PopupLine
+	Scroll    int32
+	Actions   map[rune]Command
+	visible   bool
+	wrap      bool
+	wrapWidth int
+	source    []PopupLine
+}
+
+func (p *Popup) Open(title string, lines []PopupLine, footer string, actions map[rune]Command) {
+	p.Title = title
+	p.Lines = lines
+	p.Footer = footer
+	p.Actions = actions
+	p.Scroll = 0
+	p.visible = true
+}
+
+func (p *Popup) Close() {
+	p.visible = false
+	p.Lines = nil
+	p.Actions = nil
+	p.wrap = false
+	p.source = nil
+}
+
+// SetWrap makes popup break lines longer than its width instead of cutting them
+func (p *Popup) SetWrap(wrap bool) {
+	p.wrap = wrap
+	p.wrapWidth = 0
+	p.source = p.Lines
+}
+
+// Layout rewraps lines for given width (in characters), renderer calls it before drawing
+func (p *Popup) Layout(maxNumberOfCharacters int) {
+	if !p.wrap || maxNumberOfCharacters <= 0 || p.wrapWidth == maxNumberOfCharacters {
+		return
+	}
+	p.wrapWidth = maxNumberOfCharacters
+	p.Lines = make([]PopupLine, 0, len(p.source))
+	for _, line := range p.source {
+		p.Lines = append(p.Lines, wrapPopupLine(line, maxNumberOfCharacters)...)
+	}
+	p.ScrollBy(0)
+}
+
+func wrapPopupLine(line PopupLine, maxNumberOfCharacters int) []PopupLine {
+	var (
+		wrapped []PopupLine
+		current PopupLine
+		width   int
+	)
+	for _, segment := range line {
+		text := segment.Text
+		for len(text) > 0 {
+			if width == maxNumberOfCharacters {
+				wrapped = append(wrapped, current)
+				current, width = nil, 0
+			}
+			n := min(len(text), maxNumberOfCharacters-width)
+			// Do not split multi-byte characters
+			for n > 1 && n < len(text) && !utf8.RuneStart(text[n]) {
+				n--
+			}
+			current = append(current, PopupSegment{Text: text[:n], Color: segment.Color})
+			width += n
+			text = text[n:]
+		}
+	}
+	return append(wrapped, current)
+}
+
+func (p *Popup) IsVisible() bool {
+	return p != nil && p.visible
+}
+
+func (p *Popup) ScrollBy(n int32) {
+	p.Scroll = min(max(p.Scroll+n, 0), max(int32(len(p.Lines))-1, 0))
+}
+
+func PlainPopupLine(text string, color rl.Color) PopupLine {
+	return PopupLine{{Text: text, Color: color}}
+}
+
+type PopupMode struct{}
+
+func (PopupMode) Handle(ctx *Context, k motion.Key) {
+	p := ctx.Popup
+	if k.Code == motion.KeyEsc {
+		p.Close()
+		return
+	}
+
+	switch k.Rune {
+	case 'q':
+		p.Close()
+		return
+	case 'j', rl.KeyDown:
+		p.ScrollBy(1)
+		return
+	case 'k', rl.KeyUp:
+		p.ScrollBy(-1)
+		return
+	case 'd':
+		p.ScrollBy(10)
+		return
+	case 'u':
+		p.ScrollBy(-10)
+		return
+	case 'g':
+		p.Scroll = 0
+		return
+	case 'G':
+		p.ScrollBy(int32(len(p.Lines)))
+		return
+	}
+
+	if cmd, ok := p.Actions[k.Rune]; ok && k.Modifiers == 0 {
+		slog.Debug("Popup | Trying to execute action", slog.String("cmd", fmt.Sprintf("%T", cmd)))
+		if err := cmd.Execute(ctx); err != nil {
+			slog.Error("Popup | Failed to execute action", slog.String("cmd", fmt.Sprintf("%T", cmd)), slog.Any("error", err))
+			ctx.Cursor.Common.Logs.Log(fmt.Sprintf("ERR: %s", err))
+		}
+	}
+}
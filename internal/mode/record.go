@@ -0,0 +1,45 @@
+package mode
+
+import (
+	"strings"
+
+	"github.com/quar15/qq-go/internal/database"
+	"github.com/quar15/qq-go/internal/format"
+)
+
+// RecordView shows current row of spreadsheet vertically as name / value / type lines (like `\x` of psql).
+// Rows are still stepped by spreadsheet cursor, its column is the highlighted field.
+type RecordView struct {
+	Search  string // fields whose name or value does not contain search text are hidden
+	visible bool
+}
+
+func (r *RecordView) Toggle() bool {
+	r.visible = !r.visible
+	if !r.visible {
+		r.Search = ""
+	}
+	return r.visible
+}
+
+func (r *RecordView) IsVisible() bool {
+	return r != nil && r.visible
+}
+
+// Fields returns visible columns of row matching search, all of them without search
+func (r *RecordView) Fields(dg *database.DataGrid, row int32) []int32 {
+	if row < 0 || row >= dg.Rows {
+		return nil
+	}
+	search := strings.ToLower(r.Search)
+	fields := make([]int32, 0, dg.Cols)
+	for col := int32(0); col < dg.Cols; col++ {
+		header := dg.Headers[col]
+		if search == "" ||
+			strings.Contains(strings.ToLower(header), search) ||
+			strings.Contains(strings.ToLower(format.GetDisplayValue(dg.Data[row][header])), search) {
+			fields = append(fields, col)
+		}
+	}
+	return fields
+}
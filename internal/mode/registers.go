@@ -0,0 +1,52 @@
+package mode
+
+import (
+	"fmt"
+
+	"github.com/quar15/qq-go/internal/cursor"
+	"github.com/quar15/qq-go/internal/motion"
+)
+
+// handleRegisterName reads register name after `"` in normal and visual modes or after Ctrl+R in insert mode
+func handleRegisterName(ctx *Context, k motion.Key) {
+	registers := &ctx.Cursor.Common.Registers
+	registers.Awaiting = false
+	if k.Code == motion.KeyEsc {
+		registers.Selected = 0
+		return
+	}
+
+	if ctx.Cursor.Common.Mode == cursor.ModeInsert {
+		if err := InsertRegister(ctx, k.Rune); err != nil {
+			ctx.Cursor.Common.Logs.Log(fmt.Sprintf("ERR: %s", err))
+		}
+		return
+	}
+	if err := registers.Select(k.Rune); err != nil {
+		ctx.Cursor.Common.Logs.Log(fmt.Sprintf("ERR: %s", err))
+	}
+}
+
+// YankToRegister stores text in register selected with `"` (unnamed when none)
+func YankToRegister(ctx *Context, reg cursor.Register) error {
+	name := ctx.Cursor.Common.Registers.TakeSelected()
+	return ctx.Cursor.Common.Registers.Set(name, reg)
+}
+
+// InsertRegister types content of register at editor cursor, Ctrl+R in insert mode
+func InsertRegister(ctx *Context, name rune) error {
+	if ctx.Cursor.Type != cursor.TypeEditor {
+		return nil
+	}
+	reg, err := ctx.Cursor.Common.Registers.Get(name)
+	if err != nil {
+		return err
+	}
+	lines := reg.Lines
+	if reg.Kind == cursor.RegisterLinewise {
+		lines = append(append([]string{}, lines...), "")
+	}
+	ctx.Cursor.Position.Row, ctx.Cursor.Position.Col = ctx.EditorGrid.InsertText(ctx.Cursor.Position.Row, ctx.Cursor.Position.Col, lines)
+	ctx.UpdateCursorPositionMax()
+	return nil
+}
@@ -0,0 +1,220 @@
+package mode
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/quar15/qq-go/internal/cursor"
+	"github.com/quar15/qq-go/internal/database"
+	"github.com/quar15/qq-go/internal/motion"
+)
+
+const resultTabLabelLength int = 24
+
+// ResultTab is single result kept in bottom zone, grid and cursor of inactive tab are stored in it
+type ResultTab struct {
+	Label          string // query snippet or file name
+	ConnectionName string
+	CreatedAt      time.Time
+	Pinned         bool
+	grid           database.DataGrid
+	position       motion.CursorPosition
+	commit         *pendingCommit
+}
+
+// Title describes tab in tab strip, e.g. "* select * from users | local | 14:02:11"
+func (t *ResultTab) Title() string {
+	parts := []string{t.Label}
+	if t.ConnectionName != "" {
+		parts = append(parts, t.ConnectionName)
+	}
+	parts = append(parts, t.CreatedAt.Format(time.TimeOnly))
+	title := strings.Join(parts, " | ")
+	if t.Pinned {
+		title = "* " + title
+	}
+	return title
+}
+
+// ResultTabs holds results shown in bottom zone. Active result lives in DataGrid shared by contexts,
+// so switching tabs swaps contents of that grid and position of spreadsheet cursor.
+type ResultTabs struct {
+	grid    *database.DataGrid
+	cursor  *cursor.Cursor
+	Tabs    []*ResultTab
+	Current int
+}
+
+func NewResultTabs(grid *database.DataGrid, cur *cursor.Cursor) *ResultTabs {
+	return &ResultTabs{grid: grid, cursor: cur, Current: -1}
+}
+
+// Open shows new result, it replaces current tab unless the tab is pinned or has pending changes
+func (rt *ResultTabs) Open(dg *database.DataGrid, label string, connectionName string) {
+	rt.open(dg, label, connectionName, true)
+}
+
+// OpenInNewTab shows result derived from other results (e.g. diff) always in a new tab
+func (rt *ResultTabs) OpenInNewTab(dg *database.DataGrid, label string, connectionName string) {
+	rt.open(dg, label, connectionName, false)
+}
+
+func (rt *ResultTabs) open(dg *database.DataGrid, label string, connectionName string, replace bool) {
+	rt.store()
+	tab := &ResultTab{
+		Label:          resultTabLabel(label),
+		ConnectionName: connectionName,
+		CreatedAt:      time.Now(),
+	}
+	if current := rt.CurrentTab(); replace && current != nil && !current.Pinned && !rt.hasPendingChanges(rt.Current) {
+		rt.Tabs[rt.Current] = tab
+	} else {
+		rt.Tabs = append(rt.Tabs, tab)
+		rt.Current = len(rt.Tabs) - 1
+	}
+
+	*rt.grid = *dg
+	rt.cursor.Position = motion.CursorPosition{
+		MaxCol: max(0, dg.Cols-1),
+		MaxRow: max(0, dg.Rows-1),
+	}
+}
+
+func (rt *ResultTabs) CurrentTab() *ResultTab {
+	if rt.Current < 0 || rt.Current >= len(rt.Tabs) {
+		return nil
+	}
+	return rt.Tabs[rt.Current]
+}
+
+// Grid returns result of tab, grid of current tab is the shared one
+func (rt *ResultTabs) Grid(idx int) *database.DataGrid {
+	if idx == rt.Current {
+		return rt.grid
+	}
+	return &rt.Tabs[idx].grid
+}
+
+// Switch activates tab of given index, reports if active tab changed. Tab being committed stays active until commit ends.
+func (rt *ResultTabs) Switch(idx int) (bool, error) {
+	if idx < 0 || idx >= len(rt.Tabs) || idx == rt.Current {
+		return false, nil
+	}
+	if tab := rt.CurrentTab(); tab != nil && tab.IsCommitting() {
+		return false, errors.New("Result is being committed, wait for commit to finish")
+	}
+	rt.store()
+	rt.Current = idx
+	rt.load()
+	return true, nil
+}
+
+// Cycle activates tab which is offset tabs away from the current one, wrapping around
+func (rt *ResultTabs) Cycle(offset int) (bool, error) {
+	if len(rt.Tabs) < 2 {
+		return false, nil
+	}
+	n := len(rt.Tabs)
+	return rt.Switch(((rt.Current+offset)%n + n) % n)
+}
+
+// Close removes tab, result with pending changes has to be committed or discarded first
+func (rt *ResultTabs) Close(idx int) error {
+	if idx < 0 || idx >= len(rt.Tabs) {
+		return fmt.Errorf("No result tab to close")
+	}
+	if rt.Tabs[idx].IsCommitting() {
+		return errors.New("Result is being committed, wait for commit to finish")
+	}
+	if rt.hasPendingChanges(idx) {
+		return fmt.Errorf("Result has %d pending change(s), commit or discard them first", rt.Grid(idx).Changes.Len())
+	}
+
+	rt.Tabs = append(rt.Tabs[:idx], rt.Tabs[idx+1:]...)
+	switch {
+	case idx < rt.Current:
+		rt.Current--
+	case idx == rt.Current:
+		rt.Current = min(idx, len(rt.Tabs)-1)
+		if rt.Current >= 0 {
+			rt.load()
+		} else {
+			*rt.grid = database.DataGrid{}
+			rt.cursor.Position = motion.CursorPosition{}
+		}
+	}
+	return nil
+}
+
+// TogglePin pins current tab, so next result opens in a new tab, returns new state
+func (rt *ResultTabs) TogglePin() (bool, error) {
+	tab := rt.CurrentTab()
+	if tab == nil {
+		return false, fmt.Errorf("No result to pin")
+	}
+	tab.Pinned = !tab.Pinned
+	return tab.Pinned, nil
+}
+
+func (rt *ResultTabs) hasPendingChanges(idx int) bool {
+	dg := rt.Grid(idx)
+	return dg.Changes != nil && dg.Changes.Len() > 0
+}
+
+func (rt *ResultTabs) store() {
+	if tab := rt.CurrentTab(); tab != nil {
+		tab.grid = *rt.grid
+		tab.position = rt.cursor.Position
+	}
+}
+
+func (rt *ResultTabs) load() {
+	tab := rt.Tabs[rt.Current]
+	*rt.grid = tab.grid
+	rt.cursor.Position = tab.position
+	rt.cursor.Position.ResetSelect()
+}
+
+// resultTabLabel shortens query to single line snippet
+func resultTabLabel(text string) string {
+	label := []rune(strings.Join(strings.Fields(text), " "))
+	if len(label) > resultTabLabelLength {
+		return string(label[:resultTabLabelLength-3]) + "..."
+	}
+	return string(label)
+}
+
+// resultKey identifies state of displayed result, search matches are computed again only when the key changes
+type resultKey struct {
+	data    uintptr
+	rows    int32
+	first   uintptr
+	last    uintptr
+	headers string
+	changes int // revision of pending changes
+	filter  string
+	sort    string
+}
+
+func newResultKey(dg *database.DataGrid) resultKey {
+	key := resultKey{
+		data:    reflect.ValueOf(dg.Data).Pointer(),
+		rows:    dg.Rows,
+		headers: strings.Join(dg.Headers, "\x00"),
+		sort:    dg.SortDescription(),
+	}
+	if len(dg.Data) > 0 {
+		key.first = reflect.ValueOf(dg.Data[0]).Pointer()
+		key.last = reflect.ValueOf(dg.Data[len(dg.Data)-1]).Pointer()
+	}
+	if dg.Changes != nil {
+		key.changes = dg.Changes.Revision()
+	}
+	if dg.IsFiltered() {
+		key.filter = dg.Filter.Expression
+	}
+	return key
+}
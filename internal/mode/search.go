@@ -0,0 +1,146 @@
+package mode
+
+import (
+	"fmt"
+
+	"github.com/quar15/qq-go/internal/database"
+	"github.com/quar15/qq-go/internal/motion"
+)
+
+// Search is last `/` or `?` search of spreadsheet, matches are found again whenever result changes
+type Search struct {
+	Query    database.SearchQuery
+	Backward bool // search was started with `?`, `n` goes to previous match
+	active   bool
+	key      resultKey
+	matches  []database.CellMatch
+	index    map[database.CellMatch]int
+}
+
+func (s *Search) IsActive() bool {
+	return s != nil && s.active
+}
+
+func (s *Search) Set(query database.SearchQuery, backward bool) {
+	s.Query = query
+	s.Backward = backward
+	s.active = true
+	s.key = resultKey{}
+}
+
+// Clear stops highlighting of matches, query is kept so `n` can search it again
+func (s *Search) Clear() {
+	s.active = false
+	s.matches = nil
+	s.index = nil
+}
+
+// Matches returns cells matching query in order of rows and columns
+func (s *Search) Matches(dg *database.DataGrid) []database.CellMatch {
+	if !s.IsActive() {
+		return nil
+	}
+	if key := newResultKey(dg); key != s.key {
+		s.key = key
+		s.matches = dg.FindCells(s.Query)
+		s.index = make(map[database.CellMatch]int, len(s.matches))
+		for i, m := range s.matches {
+			s.index[m] = i
+		}
+	}
+	return s.matches
+}
+
+// MatchIndex returns position of cell among matches found by last call of Matches
+func (s *Search) MatchIndex(row int32, col int32) (int, bool) {
+	if !s.IsActive() {
+		return 0, false
+	}
+	i, ok := s.index[database.CellMatch{Row: row, Col: col}]
+	return i, ok
+}
+
+// Status describes matches for status line, e.g. "match 3/57"
+func (s *Search) Status(dg *database.DataGrid, pos motion.CursorPosition) string {
+	if !s.IsActive() {
+		return ""
+	}
+	matches := s.Matches(dg)
+	if i, ok := s.MatchIndex(pos.Row, pos.Col); ok {
+		return fmt.Sprintf("match %d/%d", i+1, len(matches))
+	}
+	return fmt.Sprintf("%d matches", len(matches))
+}
+
+// Next returns match after (or before) cursor position, search wraps around end of result
+func (s *Search) Next(dg *database.DataGrid, pos motion.CursorPosition, backward bool) (database.CellMatch, bool, bool) {
+	matches := s.Matches(dg)
+	if len(matches) == 0 {
+		return database.CellMatch{}, false, false
+	}
+	after := func(m database.CellMatch) bool {
+		return m.Row > pos.Row || (m.Row == pos.Row && m.Col > pos.Col)
+	}
+	if backward {
+		for i := len(matches) - 1; i >= 0; i-- {
+			if !after(matches[i]) && matches[i] != (database.CellMatch{Row: pos.Row, Col: pos.Col}) {
+				return matches[i], false, true
+			}
+		}
+		return matches[len(matches)-1], true, true
+	}
+	for _, m := range matches {
+		if after(m) {
+			return m, false, true
+		}
+	}
+	return matches[0], true, true
+}
+
+// JumpToMatch moves spreadsheet cursor to next match in direction of search (opposite with reverse, `N`)
+func JumpToMatch(ctx *Context, reverse bool) {
+	if !ctx.Search.IsActive() {
+		if ctx.Search.Query.Text == "" {
+			ctx.Cursor.Common.Logs.Log("ERR: No previous search pattern")
+			return
+		}
+		ctx.Search.Set(ctx.Search.Query, ctx.Search.Backward)
+	}
+	backward := ctx.Search.Backward != reverse
+	match, wrapped, ok := ctx.Search.Next(ctx.DataGrid, ctx.Cursor.Position, backward)
+	if !ok {
+		ctx.Cursor.Common.Logs.Log(fmt.Sprintf("ERR: Pattern not found: %s", ctx.Search.Query.Describe()))
+		return
+	}
+	ctx.Cursor.Position.Row = match.Row
+	ctx.Cursor.Position.Col = match.Col
+
+	status := ctx.Search.Status(ctx.DataGrid, ctx.Cursor.Position)
+	switch {
+	case wrapped && backward:
+		status = "search hit TOP, continuing at BOTTOM | " + status
+	case wrapped:
+		status = "search hit BOTTOM, continuing at TOP | " + status
+	}
+	ctx.Cursor.Common.Logs.Log(status)
+}
+
+// executeSearch runs pattern typed after `/` or `?`, empty pattern repeats last search
+func executeSearch(ctx *Context, pattern string, backward bool) {
+	if pattern == "" {
+		ctx.Search.Backward = backward
+		JumpToMatch(ctx, false)
+		return
+	}
+	var column string
+	if dg := ctx.DataGrid; ctx.Cursor.Position.Col < dg.Cols {
+		column = dg.Headers[ctx.Cursor.Position.Col]
+	}
+	query, err := database.ParseSearchQuery(pattern, column)
+	if err != nil {
+		ctx.Cursor.Common.Logs.Log(fmt.Sprintf("ERR: %s", err))
+		return
+	}
+	ctx.Search.Set(query, backward)
+	JumpToMatch(ctx, false)
+}
@@ -0,0 +1,118 @@
+package mode
+
+import (
+	"fmt"
+	"log/slog"
+	"unicode"
+	"unicode/utf8"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/quar15/qq-go/internal/cursor"
+	"github.com/quar15/qq-go/internal/format"
+	"github.com/quar15/qq-go/internal/motion"
+)
+
+// handleSpreadsheetNormalKey handles editing keys of spreadsheet, returns false when key should be handled as usual
+func handleSpreadsheetNormalKey(ctx *Context, k motion.Key) bool {
+	if k.Modifiers != 0 || k.Code != motion.KeyRune {
+		return false
+	}
+	if ctx.Commands.IsPending() {
+		// Key finishes sequence, e.g. `u` of `zu` is not undo
+		return false
+	}
+
+	switch k.Rune {
+	case 'i', 'a', 'A':
+		startCellEdit(ctx)
+	case 'u':
+		if !ctx.DataGrid.Undo() {
+			ctx.Cursor.Common.Logs.Log("Already at oldest change")
+		}
+		ctx.UpdateSpreadsheetPositionMax()
+	case 'o', 'O':
+		insertGridRow(ctx, k.Rune == 'o')
+	case 's', 'S':
+		// Shift adds column as next sort key instead of replacing sort
+		sortGridByColumn(ctx, k.Rune == 'S')
+	default:
+		return false
+	}
+
+	ctx.Parser.Reset()
+	return true
+}
+
+func startCellEdit(ctx *Context) {
+	dg := ctx.DataGrid
+	pos := ctx.Cursor.Position
+	if !dg.IsEditable() {
+		ctx.Cursor.Common.Logs.Log("Result is not editable (single table with primary key required)")
+		return
+	}
+	if pos.Row >= dg.Rows || pos.Col >= dg.Cols {
+		return
+	}
+	if dg.IsPrimaryKeyColumn(dg.Headers[pos.Col]) && !dg.IsRowInserted(pos.Row) {
+		ctx.Cursor.Common.Logs.Log(fmt.Sprintf("Primary key column '%s' cannot be edited", dg.Headers[pos.Col]))
+		return
+	}
+
+	ctx.Cursor.Common.EditBuf = format.GetValueAsString(dg.Data[pos.Row][dg.Headers[pos.Col]])
+	ctx.Cursor.Common.EditOrig = ctx.Cursor.Common.EditBuf
+	ctx.Cursor.TransitionMode(cursor.ModeInsert)
+}
+
+// insertGridRow adds pending row below (or above) current one and starts editing it. Leaving the edit with Esc
+// keeps row empty, so its INSERT uses column defaults.
+func insertGridRow(ctx *Context, below bool) {
+	dg := ctx.DataGrid
+	at := ctx.Cursor.Position.Row
+	if below && dg.Rows > 0 {
+		at++
+	}
+	if err := dg.InsertRow(at); err != nil {
+		ctx.Cursor.Common.Logs.Log(err.Error())
+		return
+	}
+	ctx.Cursor.Position.Row = min(at, dg.Rows-1)
+	ctx.UpdateSpreadsheetPositionMax()
+	startCellEdit(ctx)
+}
+
+func sortGridByColumn(ctx *Context, additive bool) {
+	dg := ctx.DataGrid
+	if ctx.Cursor.Position.Col >= dg.Cols {
+		return
+	}
+	dg.CycleSort(dg.Headers[ctx.Cursor.Position.Col], additive)
+	if len(dg.SortKeys) == 0 {
+		ctx.Cursor.Common.Logs.Log("Restored original order")
+		return
+	}
+	ctx.Cursor.Common.Logs.Log(fmt.Sprintf("Sorted by %s", dg.SortDescription()))
+}
+
+// handleCellInsert types into edited cell, Enter records changed value and Esc abandons the edit
+func handleCellInsert(ctx *Context, k motion.Key) {
+	switch {
+	case k.Code == motion.KeyEsc, k.Code == motion.KeyEnter:
+		pos := ctx.Cursor.Position
+		// Unchanged text is skipped, otherwise leaving NULL cell would record it as ''
+		if k.Code == motion.KeyEnter && ctx.Cursor.Common.EditBuf != ctx.Cursor.Common.EditOrig {
+			if err := ctx.DataGrid.EditCell(pos.Row, pos.Col, ctx.Cursor.Common.EditBuf); err != nil {
+				slog.Warn("Failed to edit cell", slog.Any("error", err))
+				ctx.Cursor.Common.Logs.Log(fmt.Sprintf("ERR: %s", err))
+			}
+		}
+		ctx.Cursor.Common.EditBuf = ""
+		ctx.Cursor.Common.EditOrig = ""
+		ctx.Cursor.TransitionMode(cursor.ModeNormal)
+	case k.Code == motion.KeySpecial && k.Rune == rl.KeyBackspace:
+		buf := ctx.Cursor.Common.EditBuf
+		_, size := utf8.DecodeLastRuneInString(buf)
+		ctx.Cursor.Common.EditBuf = buf[:len(buf)-size]
+	case k.Code == motion.KeyRune && k.Modifiers == 0 && unicode.IsPrint(k.Rune):
+		ctx.Cursor.Common.EditBuf += string(k.Rune)
+	}
+}
@@ -17,8 +17,13 @@ func (VisualMode) Handle(ctx *Context, k motion.Key) {
 		ctx.Parser.Reset()
 		return
 	}
+	if k.Rune == ':' {
+		ctx.Cursor.EnterCommandMode()
+		ctx.Parser.Reset()
+		return
+	}
 
-	if cmd, ok := ctx.Commands.Lookup(k); ok {
+	if cmd, ok := lookupCommand(ctx, k); ok {
 		slog.Debug("Visual Mode | Trying to execute command", slog.String("cmd", fmt.Sprintf("%T", cmd)))
 		err := cmd.Execute(ctx)
 		if err != nil {
@@ -28,6 +33,13 @@ func (VisualMode) Handle(ctx *Context, k motion.Key) {
 	}
 
 	res := ctx.Parser.Feed(k)
+	if res.Operator != motion.OperatorNone && !res.Done {
+		// Operator works on selection right away, e.g. `d` or `gU`
+		slog.Debug("Visual Mode | Applying operator", slog.Any("res.Operator", res.Operator))
+		ctx.Parser.Reset()
+		applySelectionOperator(ctx, res.Operator)
+		return
+	}
 	if res.Done && res.Valid {
 		slog.Debug("Visual Mode | Applying motion", slog.String("res.Motion", fmt.Sprintf("%T", res.Motion)), slog.Int("res.Count", res.Count))
 		ctx.Cursor.Position = res.Motion.Apply(ctx.Cursor.Position, res.Count, res.HasCount)
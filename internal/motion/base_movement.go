@@ -38,7 +38,13 @@ func (MoveStartLeft) Apply(p CursorPosition, count int, hasCount bool) CursorPos
 }
 
 func (MoveEndRight) Apply(p CursorPosition, count int, hasCount bool) CursorPosition {
-	p.Col = p.MaxColForRows[p.Row]
+	p.Row += int32(count - 1)
+	p = p.Clamp()
+	if len(p.MaxColForRows) > 0 {
+		p.Col = p.MaxColForRows[p.Row]
+	} else {
+		p.Col = p.MaxCol
+	}
 	return p.Clamp()
 }
 
@@ -59,3 +65,10 @@ func (MoveToSpecificLineOrDown) Apply(p CursorPosition, count int, hasCount bool
 	p.Row = int32(count - 1)
 	return p.Clamp()
 }
+
+func (MoveUp) Kind() MotionKind                   { return MotionLinewise }
+func (MoveDown) Kind() MotionKind                 { return MotionLinewise }
+func (MoveStartUp) Kind() MotionKind              { return MotionLinewise }
+func (MoveEndDown) Kind() MotionKind              { return MotionLinewise }
+func (MoveToSpecificLineOrDown) Kind() MotionKind { return MotionLinewise }
+func (MoveEndRight) Kind() MotionKind             { return MotionInclusive }
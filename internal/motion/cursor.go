@@ -6,6 +6,9 @@ type CursorPosition struct {
 	MaxCol          int32
 	MaxRow          int32
 	MaxColForRows   []int32
+	Lines           []string // text of editor, empty for spreadsheet
+	ViewTopRow      int32    // first row cursor reaches without scrolling, set when zone is drawn
+	ViewBottomRow   int32    // last row cursor reaches without scrolling, set when zone is drawn
 	SelectStartCol  int32
 	SelectStartRow  int32
 	SelectEndCol    int32
@@ -17,7 +20,7 @@ type CursorPosition struct {
 func (c CursorPosition) Clamp() CursorPosition {
 	c.Row = min(max(0, c.Row), c.MaxRow)
 	if len(c.MaxColForRows) > 0 {
-		c.Col = min(max(0, c.Col), c.MaxColForRows[c.Row]-1)
+		c.Col = max(0, min(c.Col, c.MaxColForRows[c.Row]-1))
 	} else {
 		c.Col = min(max(0, c.Col), c.MaxCol)
 	}
@@ -44,8 +47,9 @@ func (c *CursorPosition) Init() {
 	c.ResetSelect()
 }
 
-func (c *CursorPosition) UpdateMax(maxCol, maxRow int32, maxColForRows []int32) {
+func (c *CursorPosition) UpdateMax(maxCol, maxRow int32, maxColForRows []int32, lines []string) {
 	c.MaxCol = maxCol
 	c.MaxRow = maxRow
 	c.MaxColForRows = maxColForRows
+	c.Lines = lines
 }
@@ -26,3 +26,4 @@ type Key struct {
 
 var CtrlW Key = Key{Code: KeyRune, Rune: rl.KeyW, Modifiers: ModCtrl}
 var CtrlE Key = Key{Code: KeyRune, Rune: rl.KeyE, Modifiers: ModCtrl}
+var CtrlR Key = Key{Code: KeyRune, Rune: rl.KeyR, Modifiers: ModCtrl}
@@ -0,0 +1,66 @@
+package motion
+
+// Operator works on text between cursor and target of following motion, e.g. `d` of `d3j`
+type Operator int8
+
+const (
+	OperatorNone Operator = iota
+	OperatorDelete
+	OperatorChange
+	OperatorYank
+	OperatorIndent
+	OperatorOutdent
+	OperatorLower
+	OperatorUpper
+	OperatorToggleCase
+)
+
+// MotionKind decides which text operator works on
+type MotionKind int8
+
+const (
+	MotionExclusive MotionKind = iota // character under target is left out, e.g. `h`
+	MotionInclusive                   // character under target is included, e.g. `$`
+	MotionLinewise                    // whole lines are included, e.g. `j`
+)
+
+// KindedMotion is implemented by motions which are not exclusive
+type KindedMotion interface {
+	Kind() MotionKind
+}
+
+func KindOf(m Motion) MotionKind {
+	if km, ok := m.(KindedMotion); ok {
+		return km.Kind()
+	}
+	return MotionExclusive
+}
+
+// TargetKindedMotion is implemented by motions whose kind depends on where they stop, e.g. `w` running out of text is inclusive
+type TargetKindedMotion interface {
+	Motion
+	TryWithKind(pos CursorPosition, count int, hasCount bool) (CursorPosition, MotionKind, bool)
+}
+
+// Target returns position operator works up to together with kind of motion, false when motion failed
+func Target(m Motion, pos CursorPosition, count int, hasCount bool) (CursorPosition, MotionKind, bool) {
+	switch mt := m.(type) {
+	case TargetKindedMotion:
+		return mt.TryWithKind(pos, count, hasCount)
+	case FallibleMotion:
+		to, ok := mt.Try(pos, count, hasCount)
+		return to, KindOf(m), ok
+	default:
+		return m.Apply(pos, count, hasCount), KindOf(m), true
+	}
+}
+
+// CurrentLines selects count lines starting with current one, used when operator is repeated, e.g. `dd`
+type CurrentLines struct{}
+
+func (CurrentLines) Apply(p CursorPosition, count int, hasCount bool) CursorPosition {
+	p.Row += int32(count - 1)
+	return p.Clamp()
+}
+
+func (CurrentLines) Kind() MotionKind { return MotionLinewise }
@@ -2,16 +2,23 @@ package motion
 
 type Result struct {
 	Motion   Motion
+	Operator Operator // operator waiting for motion (Done is false) or to apply with it
 	Count    int
 	HasCount bool
 	Done     bool
 	Valid    bool
 }
 
+// Parser reads `[count]motion` and `[count]operator[count]motion`, e.g. `3j`, `dd` or `2d3j`
 type Parser struct {
-	root    *TrieNode
-	current *TrieNode
-	count   int
+	root          *TrieNode
+	current       *TrieNode
+	count         int
+	operator      Operator
+	operatorKey   Key // last key of operator, repeating it works on lines
+	operatorCount int
+	awaiting      CharMotion // motion waiting for character, e.g. after `f`
+	lastFind      FindChar   // last `f`, `t`, `F` or `T` repeated by `;` and `,`
 }
 
 func NewParser(root *TrieNode) *Parser {
@@ -24,19 +31,45 @@ func NewParser(root *TrieNode) *Parser {
 func (p *Parser) Reset() {
 	p.current = p.root
 	p.count = 0
+	p.operator = OperatorNone
+	p.operatorCount = 0
+	p.awaiting = nil
 }
 
 func (p *Parser) ResetWith(newRoot *TrieNode) {
 	p.current = newRoot
 	p.count = 0
+	p.operator = OperatorNone
+	p.operatorCount = 0
+	p.awaiting = nil
+}
+
+// InProgress reports if parser is in the middle of sequence or waits for motion of operator
+func (p *Parser) InProgress() bool {
+	return p.current != p.root || p.operator != OperatorNone || p.awaiting != nil
 }
 
 func (p *Parser) Feed(k Key) Result {
-	if k.Code == KeyRune && k.Rune >= '0' && k.Rune <= '9' {
+	if p.awaiting != nil {
+		// Character argument of motion, e.g. `x` of `fx`
+		awaiting := p.awaiting
+		if k.Code != KeyRune || k.Modifiers != 0 {
+			p.Reset()
+			return Result{Done: true, Valid: false}
+		}
+		return p.done(awaiting.WithChar(k.Rune))
+	}
+
+	// `0` alone is motion to start of line, after other digits it is part of count
+	if k.Code == KeyRune && k.Rune >= '0' && k.Rune <= '9' && (k.Rune != '0' || p.count > 0) {
 		p.count = p.count*10 + int(k.Rune-'0')
 		return Result{Valid: true}
 	}
 
+	if p.operator != OperatorNone && p.current == p.root && k == p.operatorKey {
+		return p.done(CurrentLines{})
+	}
+
 	next := p.current.Children[k]
 	if next == nil {
 		p.Reset()
@@ -45,23 +78,56 @@ func (p *Parser) Feed(k Key) Result {
 
 	p.current = next
 
-	if next.Motion != nil {
-		c := p.count
-		hasCount := true
-		if c == 0 {
-			c = 1
-			hasCount = false
-		}
-		m := next.Motion
-		p.Reset()
-		return Result{
-			Motion:   m,
-			Count:    c,
-			HasCount: hasCount,
-			Done:     true,
-			Valid:    true,
+	if next.Operator != OperatorNone {
+		switch p.operator {
+		case OperatorNone:
+			p.operator = next.Operator
+			p.operatorKey = k
+			p.operatorCount = p.count
+			p.current = p.root
+			p.count = 0
+			return Result{Operator: p.operator, Valid: true}
+		case next.Operator:
+			return p.done(CurrentLines{})
+		default:
+			p.Reset()
+			return Result{Done: true, Valid: false}
 		}
 	}
 
+	if cm, ok := next.Motion.(CharMotion); ok {
+		p.awaiting = cm
+		return Result{Valid: true}
+	}
+	if next.Motion != nil {
+		return p.done(next.Motion)
+	}
+
 	return Result{Valid: true}
 }
+
+// done finishes parsing, counts before operator and motion multiply like in vim (`2d3j` is `d6j`)
+func (p *Parser) done(m Motion) Result {
+	switch mt := m.(type) {
+	case FindChar:
+		p.lastFind = mt
+	case RepeatFind:
+		if p.lastFind.Char == 0 {
+			p.Reset()
+			return Result{Done: true, Valid: false}
+		}
+		m = p.lastFind.Repeat(mt.Reverse)
+	}
+	c := max(p.count, 1) * max(p.operatorCount, 1)
+	hasCount := p.count > 0 || p.operatorCount > 0
+	op := p.operator
+	p.Reset()
+	return Result{
+		Motion:   m,
+		Operator: op,
+		Count:    c,
+		HasCount: hasCount,
+		Done:     true,
+		Valid:    true,
+	}
+}
@@ -0,0 +1,93 @@
+package motion
+
+import "testing"
+
+func runeKey(r rune) Key {
+	return Key{Code: KeyRune, Rune: r}
+}
+
+func newTestParser() *Parser {
+	s := NewSet()
+	s.AddRune('j', MoveDown{})
+	s.AddRune('0', MoveStartLeft{})
+	s.AddRune('w', MoveWordForward{})
+	s.AddRune('f', FindChar{})
+	s.AddRune('T', FindChar{Backward: true, Till: true})
+	s.AddRune(';', RepeatFind{})
+	s.AddRune(',', RepeatFind{Reverse: true})
+	s.Add([]Key{runeKey('g'), runeKey('g')}, MoveStartUp{})
+	s.AddOperator([]Key{runeKey('d')}, OperatorDelete)
+	s.AddOperator([]Key{runeKey('y')}, OperatorYank)
+	s.AddOperator([]Key{runeKey('g'), runeKey('U')}, OperatorUpper)
+	return NewParser(s.Root())
+}
+
+// feed types keys one by one and returns result of the last one
+func feed(p *Parser, keys string) Result {
+	var res Result
+	for _, r := range keys {
+		res = p.Feed(runeKey(r))
+	}
+	return res
+}
+
+func TestParserFeed(t *testing.T) {
+	tests := []struct {
+		keys string
+		want Result
+	}{
+		{"j", Result{Motion: MoveDown{}, Count: 1, Done: true, Valid: true}},
+		{"3j", Result{Motion: MoveDown{}, Count: 3, HasCount: true, Done: true, Valid: true}},
+		{"10j", Result{Motion: MoveDown{}, Count: 10, HasCount: true, Done: true, Valid: true}},
+		{"0", Result{Motion: MoveStartLeft{}, Count: 1, Done: true, Valid: true}},
+		{"gg", Result{Motion: MoveStartUp{}, Count: 1, Done: true, Valid: true}},
+		{"dw", Result{Motion: MoveWordForward{}, Operator: OperatorDelete, Count: 1, Done: true, Valid: true}},
+		{"dd", Result{Motion: CurrentLines{}, Operator: OperatorDelete, Count: 1, Done: true, Valid: true}},
+		{"d3d", Result{Motion: CurrentLines{}, Operator: OperatorDelete, Count: 3, HasCount: true, Done: true, Valid: true}},
+		{"2d3j", Result{Motion: MoveDown{}, Operator: OperatorDelete, Count: 6, HasCount: true, Done: true, Valid: true}},
+		{"gUU", Result{Motion: CurrentLines{}, Operator: OperatorUpper, Count: 1, Done: true, Valid: true}},
+		{"gUgU", Result{Motion: CurrentLines{}, Operator: OperatorUpper, Count: 1, Done: true, Valid: true}},
+		{"fx", Result{Motion: FindChar{Char: 'x'}, Count: 1, Done: true, Valid: true}},
+		{"y2fx", Result{Motion: FindChar{Char: 'x'}, Operator: OperatorYank, Count: 2, HasCount: true, Done: true, Valid: true}},
+		{"fx;", Result{Motion: FindChar{Char: 'x'}.Repeat(false), Count: 1, Done: true, Valid: true}},
+		{"Tx,", Result{Motion: FindChar{Char: 'x', Backward: true, Till: true}.Repeat(true), Count: 1, Done: true, Valid: true}},
+		{";", Result{Done: true}},
+		{"dy", Result{Done: true}},
+		{"q", Result{Done: true}},
+		{"d", Result{Operator: OperatorDelete, Valid: true}},
+		{"g", Result{Valid: true}},
+	}
+	for _, tt := range tests {
+		if got := feed(newTestParser(), tt.keys); got != tt.want {
+			t.Errorf("Feed(%q) = %+v, want %+v", tt.keys, got, tt.want)
+		}
+	}
+}
+
+func TestParserCharMotionNeedsRune(t *testing.T) {
+	p := newTestParser()
+	feed(p, "f")
+	if !p.InProgress() {
+		t.Fatal("parser does not wait for character after f")
+	}
+	if got := p.Feed(Key{Code: KeyEsc}); got.Valid || !got.Done {
+		t.Errorf("Feed(Esc) after f = %+v, want invalid result", got)
+	}
+	if p.InProgress() {
+		t.Error("parser is still in progress after Esc")
+	}
+}
+
+func TestParserResetKeepsLastFind(t *testing.T) {
+	p := newTestParser()
+	feed(p, "fx")
+	feed(p, "d")
+	p.Reset()
+	if p.InProgress() {
+		t.Fatal("parser is in progress after Reset")
+	}
+	want := FindChar{Char: 'x'}.Repeat(false)
+	if got := feed(p, ";"); got.Motion != want {
+		t.Errorf("; after Reset repeats %+v, want %+v", got.Motion, want)
+	}
+}
@@ -18,6 +18,11 @@ func (s *Set) Add(keys []Key, m Motion) {
 	s.root.Insert(keys, m)
 }
 
+// AddOperator binds operator which waits for motion, repeating its last key works on lines, e.g. `dd` or `gUU`
+func (s *Set) AddOperator(keys []Key, op Operator) {
+	s.root.InsertOperator(keys, op)
+}
+
 func (s *Set) AddRune(r rune, m Motion) {
 	s.Add([]Key{{Code: KeyRune, Rune: r}}, m)
 }
@@ -0,0 +1,406 @@
+package motion
+
+import "strings"
+
+// Motions below read text of editor from CursorPosition.Lines, without it (spreadsheet) word motions move by cells
+
+type MoveWordForward struct{ BigWord bool }  // `w`, `W` with BigWord
+type MoveWordBackward struct{ BigWord bool } // `b`, `B` with BigWord
+type MoveWordEnd struct{ BigWord bool }      // `e`, `E` with BigWord
+type MoveFirstNonBlank struct{}              // `^`
+type MoveParagraphForward struct{}           // `}`, paragraphs are queries separated with empty lines
+type MoveParagraphBackward struct{}          // `{`
+type MoveMatchingBracket struct{}            // `%`, with count `N%` goes to N percent of lines
+type MoveScreenTop struct{}                  // `H`
+type MoveScreenMiddle struct{}               // `M`
+type MoveScreenBottom struct{}               // `L`
+
+// FallibleMotion can fail, e.g. `f` without character on line. Operator is not applied when motion fails.
+type FallibleMotion interface {
+	Motion
+	Try(pos CursorPosition, count int, hasCount bool) (CursorPosition, bool)
+}
+
+// CharMotion needs character typed after its keys, e.g. `fx`
+type CharMotion interface {
+	Motion
+	WithChar(r rune) Motion
+}
+
+// FindChar moves to character on current line: `f` and `t` forward, `F` and `T` (Backward) back.
+// Till stops next to the character.
+type FindChar struct {
+	Char     rune
+	Backward bool
+	Till     bool
+	repeat   bool
+}
+
+// RepeatFind repeats last FindChar, `;`, or repeats it in opposite direction with Reverse, `,`
+type RepeatFind struct{ Reverse bool }
+
+type charClass int8
+
+const (
+	classBlank charClass = iota
+	classWord
+	classPunct
+	classEmptyLine // empty line is a word of its own for `w` and `b`
+)
+
+func (p CursorPosition) hasText() bool {
+	return len(p.Lines) > 0
+}
+
+// classAt returns class of character, end of line counts as blank. Bytes of multibyte characters are word.
+func (p CursorPosition) classAt(row, col int32, bigWord bool) charClass {
+	line := p.Lines[row]
+	if line == "" {
+		return classEmptyLine
+	}
+	if col >= int32(len(line)) {
+		return classBlank
+	}
+	c := line[col]
+	switch {
+	case c == ' ' || c == '\t':
+		return classBlank
+	case bigWord || c == '_' || c >= 0x80 || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9'):
+		return classWord
+	default:
+		return classPunct
+	}
+}
+
+// next returns position after given one, end of each line is position too
+func (p CursorPosition) next(row, col int32) (int32, int32, bool) {
+	if col < int32(len(p.Lines[row])) {
+		return row, col + 1, true
+	}
+	if row+1 < int32(len(p.Lines)) {
+		return row + 1, 0, true
+	}
+	return row, col, false
+}
+
+func (p CursorPosition) prev(row, col int32) (int32, int32, bool) {
+	if col > 0 {
+		return row, col - 1, true
+	}
+	if row > 0 {
+		return row - 1, int32(len(p.Lines[row-1])), true
+	}
+	return row, col, false
+}
+
+func (p CursorPosition) firstNonBlank(row int32) int32 {
+	line := p.Lines[row]
+	return int32(len(line) - len(strings.TrimLeft(line, " \t")))
+}
+
+func (m MoveWordForward) Apply(p CursorPosition, count int, hasCount bool) CursorPosition {
+	found, _, _ := m.TryWithKind(p, count, hasCount)
+	return found
+}
+
+// TryWithKind moves like Apply, motion is inclusive when it runs out of text, so `dw` on last word deletes all of it
+func (m MoveWordForward) TryWithKind(p CursorPosition, count int, hasCount bool) (CursorPosition, MotionKind, bool) {
+	if !p.hasText() {
+		return MoveRight{}.Apply(p, count, hasCount), MotionExclusive, true
+	}
+	row, col := p.Row, p.Col
+	kind := MotionExclusive
+	for range count {
+		ok := true
+		switch cls := p.classAt(row, col, m.BigWord); cls {
+		case classEmptyLine:
+			row, col, ok = p.next(row, col)
+		case classWord, classPunct:
+			for ok && p.classAt(row, col, m.BigWord) == cls {
+				row, col, ok = p.next(row, col)
+			}
+		}
+		for ok && p.classAt(row, col, m.BigWord) == classBlank {
+			row, col, ok = p.next(row, col)
+		}
+		if !ok {
+			kind = MotionInclusive
+			break
+		}
+	}
+	p.Row, p.Col = row, col
+	return p.Clamp(), kind, true
+}
+
+func (m MoveWordBackward) Apply(p CursorPosition, count int, hasCount bool) CursorPosition {
+	if !p.hasText() {
+		return MoveLeft{}.Apply(p, count, hasCount)
+	}
+	row, col := p.Row, p.Col
+	for range count {
+		r, c, ok := p.prev(row, col)
+		for ok && p.classAt(r, c, m.BigWord) == classBlank {
+			r, c, ok = p.prev(r, c)
+		}
+		row, col = r, c
+		if !ok {
+			break
+		}
+		cls := p.classAt(row, col, m.BigWord)
+		for cls != classEmptyLine {
+			r, c, ok := p.prev(row, col)
+			if !ok || r != row || p.classAt(r, c, m.BigWord) != cls {
+				break
+			}
+			row, col = r, c
+		}
+	}
+	p.Row, p.Col = row, col
+	return p.Clamp()
+}
+
+func (m MoveWordEnd) Apply(p CursorPosition, count int, hasCount bool) CursorPosition {
+	if !p.hasText() {
+		return MoveRight{}.Apply(p, count, hasCount)
+	}
+	row, col := p.Row, p.Col
+	for range count {
+		r, c, ok := p.next(row, col)
+		for ok && (p.classAt(r, c, m.BigWord) == classBlank || p.classAt(r, c, m.BigWord) == classEmptyLine) {
+			r, c, ok = p.next(r, c)
+		}
+		if !ok {
+			break
+		}
+		row, col = r, c
+		cls := p.classAt(row, col, m.BigWord)
+		for {
+			r, c, ok := p.next(row, col)
+			if !ok || r != row || p.classAt(r, c, m.BigWord) != cls {
+				break
+			}
+			row, col = r, c
+		}
+	}
+	p.Row, p.Col = row, col
+	return p.Clamp()
+}
+
+func (MoveWordEnd) Kind() MotionKind { return MotionInclusive }
+
+func (MoveFirstNonBlank) Apply(p CursorPosition, count int, hasCount bool) CursorPosition {
+	if !p.hasText() {
+		return MoveStartLeft{}.Apply(p, count, hasCount)
+	}
+	p.Col = p.firstNonBlank(p.Row)
+	return p.Clamp()
+}
+
+func (f FindChar) WithChar(r rune) Motion {
+	f.Char = r
+	return f
+}
+
+// Repeat returns find used by `;` (or `,` with reverse), till does not get stuck in front of found character
+func (f FindChar) Repeat(reverse bool) FindChar {
+	if reverse {
+		f.Backward = !f.Backward
+	}
+	f.repeat = true
+	return f
+}
+
+func (f FindChar) Apply(p CursorPosition, count int, hasCount bool) CursorPosition {
+	found, _ := f.Try(p, count, hasCount)
+	return found
+}
+
+func (f FindChar) Try(p CursorPosition, count int, hasCount bool) (CursorPosition, bool) {
+	if !p.hasText() || f.Char == 0 {
+		return p, false
+	}
+	line := p.Lines[p.Row]
+	needle := string(f.Char)
+	idx := int(p.Col)
+	for i := range count {
+		skip := 1
+		if i == 0 && f.repeat && f.Till {
+			skip = 2
+		}
+		if f.Backward {
+			to := idx - skip + 1
+			if to <= 0 {
+				return p, false
+			}
+			idx = strings.LastIndex(line[:to], needle)
+		} else {
+			from := idx + skip
+			if from >= len(line) {
+				return p, false
+			}
+			if found := strings.Index(line[from:], needle); found >= 0 {
+				idx = from + found
+			} else {
+				idx = -1
+			}
+		}
+		if idx < 0 {
+			return p, false
+		}
+	}
+
+	switch {
+	case f.Till && f.Backward:
+		idx++
+	case f.Till:
+		idx--
+	}
+	p.Col = int32(idx)
+	return p.Clamp(), true
+}
+
+func (f FindChar) Kind() MotionKind {
+	if f.Backward {
+		return MotionExclusive
+	}
+	return MotionInclusive
+}
+
+// Apply of RepeatFind keeps position, parser replaces it with last find
+func (RepeatFind) Apply(p CursorPosition, count int, hasCount bool) CursorPosition {
+	return p
+}
+
+func (MoveParagraphForward) Apply(p CursorPosition, count int, hasCount bool) CursorPosition {
+	if !p.hasText() {
+		return p
+	}
+	last := int32(len(p.Lines)) - 1
+	row := p.Row
+	for range count {
+		for row < last && p.Lines[row] == "" {
+			row++
+		}
+		for row < last && p.Lines[row] != "" {
+			row++
+		}
+	}
+	p.Row, p.Col = row, 0
+	if row == last {
+		// Like in vim last paragraph ends at end of buffer
+		p.Col = int32(len(p.Lines[row]))
+	}
+	return p.Clamp()
+}
+
+func (MoveParagraphBackward) Apply(p CursorPosition, count int, hasCount bool) CursorPosition {
+	if !p.hasText() {
+		return p
+	}
+	row := p.Row
+	for range count {
+		for row > 0 && p.Lines[row] == "" {
+			row--
+		}
+		for row > 0 && p.Lines[row] != "" {
+			row--
+		}
+	}
+	p.Row, p.Col = row, 0
+	return p.Clamp()
+}
+
+func (m MoveMatchingBracket) Apply(p CursorPosition, count int, hasCount bool) CursorPosition {
+	found, _ := m.Try(p, count, hasCount)
+	return found
+}
+
+func (m MoveMatchingBracket) Try(p CursorPosition, count int, hasCount bool) (CursorPosition, bool) {
+	found, _, ok := m.TryWithKind(p, count, hasCount)
+	return found, ok
+}
+
+// TryWithKind finds first bracket at or after cursor on its line and moves to its pair, nesting is counted across lines.
+// With count it moves to line at count percent of text instead, like in vim that is linewise.
+func (MoveMatchingBracket) TryWithKind(p CursorPosition, count int, hasCount bool) (CursorPosition, MotionKind, bool) {
+	if hasCount {
+		if count > 100 {
+			return p, MotionLinewise, false
+		}
+		lines := p.MaxRow + 1
+		if p.hasText() {
+			lines = int32(len(p.Lines))
+		}
+		p.Row = max((int32(count)*lines+99)/100-1, 0)
+		return p.onScreenRow(), MotionLinewise, true
+	}
+	found, ok := matchingBracket(p)
+	return found, MotionInclusive, ok
+}
+
+func matchingBracket(p CursorPosition) (CursorPosition, bool) {
+	if !p.hasText() {
+		return p, false
+	}
+	const brackets = "()[]{}"
+	line := p.Lines[p.Row]
+	start := strings.IndexAny(line[min(int(p.Col), len(line)):], brackets)
+	if start < 0 {
+		return p, false
+	}
+	col := p.Col + int32(start)
+	bracket := line[col]
+	i := strings.IndexByte(brackets, bracket)
+	pair := brackets[i^1]
+	forward := i%2 == 0
+
+	row, depth, ok := p.Row, 0, true
+	for ok {
+		if col < int32(len(p.Lines[row])) {
+			switch p.Lines[row][col] {
+			case bracket:
+				depth++
+			case pair:
+				depth--
+			}
+			if depth == 0 {
+				p.Row, p.Col = row, col
+				return p.Clamp(), true
+			}
+		}
+		if forward {
+			row, col, ok = p.next(row, col)
+		} else {
+			row, col, ok = p.prev(row, col)
+		}
+	}
+	return p, false
+}
+
+func (MoveScreenTop) Apply(p CursorPosition, count int, hasCount bool) CursorPosition {
+	p.Row = min(p.ViewTopRow+int32(count-1), p.ViewBottomRow)
+	return p.onScreenRow()
+}
+
+func (MoveScreenMiddle) Apply(p CursorPosition, count int, hasCount bool) CursorPosition {
+	p.Row = (p.ViewTopRow + p.ViewBottomRow) / 2
+	return p.onScreenRow()
+}
+
+func (MoveScreenBottom) Apply(p CursorPosition, count int, hasCount bool) CursorPosition {
+	p.Row = max(p.ViewBottomRow-int32(count-1), p.ViewTopRow)
+	return p.onScreenRow()
+}
+
+func (MoveScreenTop) Kind() MotionKind    { return MotionLinewise }
+func (MoveScreenMiddle) Kind() MotionKind { return MotionLinewise }
+func (MoveScreenBottom) Kind() MotionKind { return MotionLinewise }
+
+// onScreenRow clamps row reached by `H`, `M`, `L` or `N%` and moves to its first non blank character
+func (p CursorPosition) onScreenRow() CursorPosition {
+	p = p.Clamp()
+	if p.hasText() {
+		p.Col = p.firstNonBlank(p.Row)
+	}
+	return p.Clamp()
+}
@@ -0,0 +1,150 @@
+package motion
+
+import "testing"
+
+// textPosition places cursor into editor text the way editor mode fills CursorPosition
+func textPosition(lines []string, row, col int32) CursorPosition {
+	cols := make([]int32, len(lines))
+	for i, line := range lines {
+		cols[i] = int32(len(line))
+	}
+	return CursorPosition{
+		Row:           row,
+		Col:           col,
+		MaxRow:        int32(len(lines)) - 1,
+		MaxCol:        max(cols[row]-1, 0),
+		MaxColForRows: cols,
+		Lines:         lines,
+	}
+}
+
+func TestTextMotionTarget(t *testing.T) {
+	words := []string{"foo bar", "", "baz.qux  end"}
+	finds := []string{"a,b,c"}
+	paragraphs := []string{"select 1;", "", "select 2;", "from t;"}
+	brackets := []string{"f(a[1])", "(", "  x", ")"}
+
+	tests := []struct {
+		name     string
+		motion   Motion
+		lines    []string
+		row, col int32
+		count    int
+		hasCount bool
+		wantRow  int32
+		wantCol  int32
+		wantKind MotionKind
+		wantOk   bool
+	}{
+		{"w to next word", MoveWordForward{}, words, 0, 0, 1, false, 0, 4, MotionExclusive, true},
+		{"w stops on empty line", MoveWordForward{}, words, 0, 4, 1, false, 1, 0, MotionExclusive, true},
+		{"w leaves empty line", MoveWordForward{}, words, 1, 0, 1, false, 2, 0, MotionExclusive, true},
+		{"w stops on punctuation", MoveWordForward{}, words, 2, 0, 1, false, 2, 3, MotionExclusive, true},
+		{"2w", MoveWordForward{}, words, 2, 0, 2, true, 2, 4, MotionExclusive, true},
+		{"w on last word is inclusive", MoveWordForward{}, words, 2, 9, 1, false, 2, 11, MotionInclusive, true},
+		{"W skips punctuation", MoveWordForward{BigWord: true}, words, 2, 0, 1, false, 2, 9, MotionExclusive, true},
+		{"b to punctuation", MoveWordBackward{}, words, 2, 4, 1, false, 2, 3, MotionExclusive, true},
+		{"b stops on empty line", MoveWordBackward{}, words, 2, 0, 1, false, 1, 0, MotionExclusive, true},
+		{"b to start of word", MoveWordBackward{}, words, 0, 6, 1, false, 0, 4, MotionExclusive, true},
+		{"e to end of word", MoveWordEnd{}, words, 0, 0, 1, false, 0, 2, MotionInclusive, true},
+		{"e from end of word", MoveWordEnd{}, words, 0, 2, 1, false, 0, 6, MotionInclusive, true},
+		{"^", MoveFirstNonBlank{}, brackets, 2, 0, 1, false, 2, 2, MotionExclusive, true},
+		{"f", FindChar{Char: ','}, finds, 0, 0, 1, false, 0, 1, MotionInclusive, true},
+		{"2f", FindChar{Char: ','}, finds, 0, 0, 2, true, 0, 3, MotionInclusive, true},
+		{"3f fails", FindChar{Char: ','}, finds, 0, 0, 3, true, 0, 0, MotionInclusive, false},
+		{"t", FindChar{Char: ',', Till: true}, finds, 0, 1, 1, false, 0, 2, MotionInclusive, true},
+		{"; of t skips found character", FindChar{Char: ',', Till: true}.Repeat(false), finds, 0, 0, 1, false, 0, 2, MotionInclusive, true},
+		{"F", FindChar{Char: ',', Backward: true}, finds, 0, 4, 1, false, 0, 3, MotionExclusive, true},
+		{"T", FindChar{Char: ',', Backward: true, Till: true}, finds, 0, 4, 1, false, 0, 4, MotionExclusive, true},
+		{"F fails at start", FindChar{Char: ',', Backward: true}, finds, 0, 0, 1, false, 0, 0, MotionExclusive, false},
+		{"} to empty line", MoveParagraphForward{}, paragraphs, 0, 0, 1, false, 1, 0, MotionExclusive, true},
+		{"} to end of text", MoveParagraphForward{}, paragraphs, 1, 0, 1, false, 3, 6, MotionExclusive, true},
+		{"{", MoveParagraphBackward{}, paragraphs, 3, 3, 1, false, 1, 0, MotionExclusive, true},
+		{"% from before bracket", MoveMatchingBracket{}, brackets, 0, 0, 1, false, 0, 6, MotionInclusive, true},
+		{"% of inner bracket", MoveMatchingBracket{}, brackets, 0, 3, 1, false, 0, 5, MotionInclusive, true},
+		{"% back to opening bracket", MoveMatchingBracket{}, brackets, 0, 6, 1, false, 0, 1, MotionInclusive, true},
+		{"% across lines", MoveMatchingBracket{}, brackets, 1, 0, 1, false, 3, 0, MotionInclusive, true},
+		{"% without bracket fails", MoveMatchingBracket{}, words, 0, 0, 1, false, 0, 0, MotionInclusive, false},
+		{"50%", MoveMatchingBracket{}, brackets, 0, 0, 50, true, 1, 0, MotionLinewise, true},
+		{"75% goes to first non blank", MoveMatchingBracket{}, brackets, 0, 0, 75, true, 2, 2, MotionLinewise, true},
+		{"100%", MoveMatchingBracket{}, brackets, 0, 0, 100, true, 3, 0, MotionLinewise, true},
+		{"101% fails", MoveMatchingBracket{}, brackets, 0, 0, 101, true, 0, 0, MotionLinewise, false},
+	}
+	for _, tt := range tests {
+		to, kind, ok := Target(tt.motion, textPosition(tt.lines, tt.row, tt.col), tt.count, tt.hasCount)
+		if ok != tt.wantOk || kind != tt.wantKind {
+			t.Errorf("%s: kind %d ok %t, want kind %d ok %t", tt.name, kind, ok, tt.wantKind, tt.wantOk)
+		}
+		if ok && (to.Row != tt.wantRow || to.Col != tt.wantCol) {
+			t.Errorf("%s: moved to (%d, %d), want (%d, %d)", tt.name, to.Row, to.Col, tt.wantRow, tt.wantCol)
+		}
+	}
+}
+
+func TestScreenMotions(t *testing.T) {
+	lines := make([]string, 10)
+	for i := range lines {
+		lines[i] = "  x"
+	}
+	tests := []struct {
+		name    string
+		motion  Motion
+		count   int
+		wantRow int32
+	}{
+		{"H", MoveScreenTop{}, 1, 2},
+		{"3H", MoveScreenTop{}, 3, 4},
+		{"M", MoveScreenMiddle{}, 1, 5},
+		{"L", MoveScreenBottom{}, 1, 8},
+		{"2L", MoveScreenBottom{}, 2, 7},
+		{"20L stays on screen", MoveScreenBottom{}, 20, 2},
+	}
+	for _, tt := range tests {
+		p := textPosition(lines, 0, 0)
+		p.ViewTopRow, p.ViewBottomRow = 2, 8
+		to := tt.motion.Apply(p, tt.count, tt.count > 1)
+		if to.Row != tt.wantRow || to.Col != 2 {
+			t.Errorf("%s: moved to (%d, %d), want (%d, 2)", tt.name, to.Row, to.Col, tt.wantRow)
+		}
+		if KindOf(tt.motion) != MotionLinewise {
+			t.Errorf("%s is not linewise", tt.name)
+		}
+	}
+}
+
+func TestTextMotionsWithoutText(t *testing.T) {
+	// Spreadsheet has no text, word motions move by cells
+	p := CursorPosition{Row: 1, Col: 1, MaxCol: 3, MaxRow: 9}
+	tests := []struct {
+		name     string
+		motion   Motion
+		count    int
+		hasCount bool
+		wantRow  int32
+		wantCol  int32
+		wantOk   bool
+	}{
+		{"w", MoveWordForward{}, 1, false, 1, 2, true},
+		{"5w stops at last column", MoveWordForward{}, 5, true, 1, 3, true},
+		{"b", MoveWordBackward{}, 1, false, 1, 0, true},
+		{"e", MoveWordEnd{}, 1, false, 1, 2, true},
+		{"f fails", FindChar{Char: 'x'}, 1, false, 1, 1, false},
+		{"50%", MoveMatchingBracket{}, 50, true, 4, 1, true},
+	}
+	for _, tt := range tests {
+		to, _, ok := Target(tt.motion, p, tt.count, tt.hasCount)
+		if ok != tt.wantOk || (ok && (to.Row != tt.wantRow || to.Col != tt.wantCol)) {
+			t.Errorf("%s: moved to (%d, %d) ok %t, want (%d, %d) ok %t", tt.name, to.Row, to.Col, ok, tt.wantRow, tt.wantCol, tt.wantOk)
+		}
+	}
+}
+
+func TestCurrentLines(t *testing.T) {
+	p := textPosition([]string{"a", "b", "c"}, 1, 0)
+	if to := (CurrentLines{}).Apply(p, 5, true); to.Row != 2 {
+		t.Errorf("5 lines from second of three reach row %d, want 2", to.Row)
+	}
+	if KindOf(CurrentLines{}) != MotionLinewise {
+		t.Error("CurrentLines is not linewise")
+	}
+}
@@ -3,6 +3,7 @@ package motion
 type TrieNode struct {
 	Children map[Key]*TrieNode
 	Motion   Motion
+	Operator Operator
 }
 
 func NewTrie() *TrieNode {
@@ -12,6 +13,16 @@ func NewTrie() *TrieNode {
 }
 
 func (t *TrieNode) Insert(keys []Key, m Motion) {
+	node := t.node(keys)
+	node.Motion = m
+	// slog.Debug("Inserted motion into Trie", slog.String("motion", fmt.Sprintf("%T", m)))
+}
+
+func (t *TrieNode) InsertOperator(keys []Key, op Operator) {
+	t.node(keys).Operator = op
+}
+
+func (t *TrieNode) node(keys []Key) *TrieNode {
 	node := t
 	for _, k := range keys {
 		if node.Children[k] == nil {
@@ -19,6 +30,5 @@ func (t *TrieNode) Insert(keys []Key, m Motion) {
 		}
 		node = node.Children[k]
 	}
-	node.Motion = m
-	// slog.Debug("Inserted motion into Trie", slog.String("motion", fmt.Sprintf("%T", m)))
+	return node
 }
@@ -4,6 +4,7 @@ import (
 	"log/slog"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/quar15/qq-go/internal/export"
 	"github.com/quar15/qq-go/internal/mode"
 	"github.com/quar15/qq-go/internal/mode/commands"
 	"github.com/quar15/qq-go/internal/motion"
@@ -34,25 +35,61 @@ func baseMotionSet() *motion.Set {
 		{Code: motion.KeyRune, Rune: keySmallG},
 	}, motion.MoveStartUp{})
 
-	s.Add([]motion.Key{
-		{Code: motion.KeyRune, Rune: keySmallD},
-		{Code: motion.KeyRune, Rune: keySmallD},
-	}, motion.DebugMotion{})
+	addTextMotions(s)
 
 	return s
 }
 
+// addTextMotions adds word, line, find, bracket, paragraph and screen motions, they read text of cursor position
+func addTextMotions(s *motion.Set) {
+	s.AddRune('w', motion.MoveWordForward{})
+	s.AddRune('W', motion.MoveWordForward{BigWord: true})
+	s.AddRune('b', motion.MoveWordBackward{})
+	s.AddRune('B', motion.MoveWordBackward{BigWord: true})
+	s.AddRune('e', motion.MoveWordEnd{})
+	s.AddRune('E', motion.MoveWordEnd{BigWord: true})
+	s.AddRune('0', motion.MoveStartLeft{})
+	s.AddRune('^', motion.MoveFirstNonBlank{})
+	s.AddRune('$', motion.MoveEndRight{})
+
+	s.AddRune('f', motion.FindChar{})
+	s.AddRune('t', motion.FindChar{Till: true})
+	s.AddRune('F', motion.FindChar{Backward: true})
+	s.AddRune('T', motion.FindChar{Backward: true, Till: true})
+	s.AddRune(';', motion.RepeatFind{})
+	s.AddRune(',', motion.RepeatFind{Reverse: true})
+
+	s.AddRune('%', motion.MoveMatchingBracket{})
+	s.AddRune('}', motion.MoveParagraphForward{})
+	s.AddRune('{', motion.MoveParagraphBackward{})
+	s.AddRune('H', motion.MoveScreenTop{})
+	s.AddRune('M', motion.MoveScreenMiddle{})
+	s.AddRune('L', motion.MoveScreenBottom{})
+}
+
 func baseCommandRegistry() *mode.CommandRegistry {
 	cr := mode.NewCommandRegistry()
 
 	cr.Bind(motion.Key{Code: motion.KeyRune, Rune: 'E', Modifiers: motion.ModCtrl}, commands.ConnectionsSwap{})
 	cr.Bind(motion.Key{Code: motion.KeyRune, Rune: 'W', Modifiers: motion.ModCtrl}, mode.WindowManagementModeActivate{})
 
+	cr.BindEx("listen", commands.Listen{})
+	cr.BindEx("unlisten", commands.Unlisten{})
+
 	return cr
 }
 
 func EditorMotionSet() (*motion.Set, *mode.CommandRegistry) {
 	s := baseMotionSet()
+	s.AddOperator([]motion.Key{{Code: motion.KeyRune, Rune: keySmallD}}, motion.OperatorDelete)
+	s.AddOperator([]motion.Key{{Code: motion.KeyRune, Rune: 'c'}}, motion.OperatorChange)
+	s.AddOperator([]motion.Key{{Code: motion.KeyRune, Rune: 'y'}}, motion.OperatorYank)
+	s.AddOperator([]motion.Key{{Code: motion.KeyRune, Rune: '>'}}, motion.OperatorIndent)
+	s.AddOperator([]motion.Key{{Code: motion.KeyRune, Rune: '<'}}, motion.OperatorOutdent)
+	s.AddOperator([]motion.Key{{Code: motion.KeyRune, Rune: keySmallG}, {Code: motion.KeyRune, Rune: 'u'}}, motion.OperatorLower)
+	s.AddOperator([]motion.Key{{Code: motion.KeyRune, Rune: keySmallG}, {Code: motion.KeyRune, Rune: 'U'}}, motion.OperatorUpper)
+	s.AddOperator([]motion.Key{{Code: motion.KeyRune, Rune: keySmallG}, {Code: motion.KeyRune, Rune: '~'}}, motion.OperatorToggleCase)
+
 	cr := baseCommandRegistry()
 	cr.Bind(
 		motion.Key{Code: motion.KeyEnter, Rune: rl.KeyEnter, Modifiers: motion.ModCtrl},
@@ -62,6 +99,11 @@ func EditorMotionSet() (*motion.Set, *mode.CommandRegistry) {
 		motion.Key{Code: motion.KeyRune, Rune: rl.KeyC, Modifiers: motion.ModCtrl},
 		commands.CopyToClipboardEditor{},
 	)
+	cr.Bind(motion.Key{Code: motion.KeyRune, Rune: '"'}, commands.SelectRegister{})
+	cr.Bind(motion.Key{Code: motion.KeyRune, Rune: 'p'}, commands.PasteEditor{})
+	cr.Bind(motion.Key{Code: motion.KeyRune, Rune: 'P'}, commands.PasteEditor{Before: true})
+	cr.Bind(motion.Key{Code: motion.KeyRune, Rune: 'u'}, commands.UndoEditor{})
+	cr.Bind(motion.CtrlR, commands.RedoEditor{})
 
 	slog.Debug("Initialized editor motion set", slog.Any("setTrie", s.Root()))
 	return s, cr
@@ -69,11 +111,79 @@ func EditorMotionSet() (*motion.Set, *mode.CommandRegistry) {
 
 func SpreadsheetMotionSet() (*motion.Set, *mode.CommandRegistry) {
 	s := baseMotionSet()
+	// Only yank makes sense for result, `yy` copies row and `yl` cell
+	s.AddOperator([]motion.Key{{Code: motion.KeyRune, Rune: 'y'}}, motion.OperatorYank)
+
 	cr := baseCommandRegistry()
 	cr.Bind(
 		motion.Key{Code: motion.KeyRune, Rune: rl.KeyC, Modifiers: motion.ModCtrl},
-		commands.CopyToClipboardSpreadsheet{},
+		commands.CopyToClipboardSpreadsheet{Format: export.FormatTSV},
 	)
+	cr.BindEx("import", commands.ImportDataGrid{})
+	cr.BindEx("export", commands.ExportDataGrid{})
+	cr.BindEx("review", commands.ReviewChanges{})
+	cr.BindEx("commit", commands.CommitChanges{})
+	cr.BindEx("discard", commands.DiscardChanges{})
+	cr.BindEx("null", commands.SetCellNull{})
+	cr.BindEx("filter", commands.FilterDataGrid{})
+	cr.BindEx("nofilter", commands.ClearFilter{})
+	cr.BindEx("stats", commands.ShowColumnStats{})
+	cr.BindEx("width", commands.SetColumnWidth{})
+	cr.BindEx("fit", commands.FitColumn{})
+	cr.BindEx("fitall", commands.FitAllColumns{})
+	cr.BindEx("hide", commands.HideColumn{})
+	cr.BindEx("unhide", commands.UnhideColumns{})
+	cr.BindEx("freeze", commands.FreezeColumns{})
+	cr.BindEx("unfreeze", commands.UnfreezeColumns{})
+	cr.BindEx("resetcolumns", commands.ResetColumns{})
+	cr.BindEx("pin", commands.PinResultTab{})
+	cr.BindEx("tab", commands.SwitchResultTab{})
+	cr.BindEx("tabclose", commands.CloseResultTab{})
+	cr.BindEx("diff", commands.DiffResults{})
+	cr.BindEx("chart", commands.ShowChart{})
+	cr.BindEx("pivot", commands.PivotResult{})
+	cr.BindEx("x", commands.ToggleRecordView{})
+	cr.BindEx("noh", commands.ClearSearch{})
+	cr.BindEx("copy", commands.CopyToClipboardSpreadsheet{Format: export.FormatTSV})
+	cr.Bind(motion.Key{Code: motion.KeyRune, Rune: '='}, commands.QuickFilter{})
+	cr.Bind(motion.Key{Code: motion.KeyRune, Rune: '!'}, commands.QuickFilter{Negate: true})
+	cr.Bind(motion.Key{Code: motion.KeyRune, Rune: 'K'}, commands.InspectCell{})
+	cr.Bind(motion.Key{Code: motion.KeyEnter, Rune: rl.KeyEnter}, commands.InspectCell{})
+	cr.Bind(motion.Key{Code: motion.KeyRune, Rune: '+'}, commands.ResizeColumn{Delta: 1})
+	cr.Bind(motion.Key{Code: motion.KeyRune, Rune: '-'}, commands.ResizeColumn{Delta: -1})
+	cr.Bind(motion.Key{Code: motion.KeyRune, Rune: '_'}, commands.FitColumn{})
+	cr.Bind(motion.Key{Code: motion.KeyRune, Rune: '/'}, commands.StartSearch{})
+	cr.Bind(motion.Key{Code: motion.KeyRune, Rune: '?'}, commands.StartSearch{Backward: true})
+	cr.Bind(motion.Key{Code: motion.KeyRune, Rune: 'n'}, commands.SearchNext{})
+	cr.Bind(motion.Key{Code: motion.KeyRune, Rune: 'N'}, commands.SearchNext{Reverse: true})
+	cr.Bind(motion.Key{Code: motion.KeyRune, Rune: '"'}, commands.SelectRegister{})
+	cr.BindSequence([]motion.Key{
+		{Code: motion.KeyRune, Rune: keySmallD},
+		{Code: motion.KeyRune, Rune: keySmallD},
+	}, commands.ToggleRowsDeleted{})
+	for r, cmd := range map[rune]mode.Command{
+		'h': commands.HideColumn{},
+		'u': commands.UnhideColumns{},
+		'H': commands.MoveColumn{Offset: -1},
+		'L': commands.MoveColumn{Offset: 1},
+		'f': commands.FreezeColumns{},
+		'F': commands.UnfreezeColumns{},
+		'x': commands.ToggleRecordView{},
+	} {
+		cr.BindSequence([]motion.Key{{Code: motion.KeyRune, Rune: 'z'}, {Code: motion.KeyRune, Rune: r}}, cmd)
+	}
+	cr.BindSequence([]motion.Key{
+		{Code: motion.KeyRune, Rune: keySmallG},
+		{Code: motion.KeyRune, Rune: 'y'},
+	}, commands.CopyFormatPrompt{})
+	cr.BindSequence([]motion.Key{
+		{Code: motion.KeyRune, Rune: keySmallG},
+		{Code: motion.KeyRune, Rune: 't'},
+	}, commands.CycleResultTab{Offset: 1})
+	cr.BindSequence([]motion.Key{
+		{Code: motion.KeyRune, Rune: keySmallG},
+		{Code: motion.KeyRune, Rune: 'T'},
+	}, commands.CycleResultTab{Offset: -1})
 
 	slog.Debug("Initialized spreadsheet motion set", slog.Any("setTrie", s.Root()))
 	return s, cr
@@ -94,6 +204,8 @@ func ConnectionsMotionSet() (*motion.Set, *mode.CommandRegistry) {
 		{Code: motion.KeyRune, Rune: keySmallG},
 	}, motion.MoveStartUp{})
 
+	addTextMotions(s)
+
 	cr := baseCommandRegistry()
 	cr.Bind(motion.Key{Code: motion.KeyEnter, Rune: rl.KeyEnter}, commands.ConnectionsChange{})
 	cr.Bind(motion.Key{Code: motion.KeyEsc, Rune: rl.KeyEscape}, commands.ConnectionsExit{})
@@ -35,13 +35,22 @@ type App struct {
 	zones     *zones
 	cursors   *cursors
 	windowMgr *mode.WindowManager
+	popup     *mode.Popup
+	results   *mode.ResultTabs
+	chart     *mode.ChartView
+	record    *mode.RecordView
+	search    *mode.Search
 }
 
 type zones struct {
-	top         display.Zone
-	bottom      display.Zone
-	command     display.Zone
-	connections display.Zone
+	top           display.Zone
+	bottom        display.Zone
+	command       display.Zone
+	connections   display.Zone
+	notifications display.Zone
+	resultTabs    display.Zone
+	chart         display.Zone
+	record        display.Zone
 }
 
 type cursors struct {
@@ -93,7 +102,7 @@ func newApp(cfg *config.Config) *App {
 	connMgr := database.NewConnectionManager(cfg.Connections, &database.DefaultConnectionFactory{})
 
 	dg := &database.DataGrid{}
-	eg := editor.NewGrid()
+	eg := editor.NewGrid(cfg.Editor.UndoLevels)
 
 	cursorCommon := &cursor.Common{}
 	cursorCommon.Logs.Init()
@@ -105,6 +114,18 @@ func newApp(cfg *config.Config) *App {
 	}
 	appCursors.connections.Cursor.Position.MaxRow = int32(len(cfg.Connections) - 1)
 	windowMgr := appCursors.initWindowManager()
+	popup := &mode.Popup{}
+	appCursors.editor.Popup = popup
+	appCursors.spreadsheet.Popup = popup
+	appCursors.connections.Popup = popup
+	results := mode.NewResultTabs(dg, appCursors.spreadsheet.Cursor)
+	appCursors.spreadsheet.Results = results
+	chart := &mode.ChartView{}
+	appCursors.spreadsheet.Chart = chart
+	record := &mode.RecordView{}
+	appCursors.spreadsheet.Record = record
+	search := &mode.Search{}
+	appCursors.spreadsheet.Search = search
 
 	app := &App{
 		cfg:      cfg,
@@ -119,6 +140,11 @@ func newApp(cfg *config.Config) *App {
 		zones:     &zones{},
 		cursors:   appCursors,
 		windowMgr: windowMgr,
+		popup:     popup,
+		results:   results,
+		chart:     chart,
+		record:    record,
+		search:    search,
 	}
 
 	return app
@@ -126,6 +152,7 @@ func newApp(cfg *config.Config) *App {
 
 func (a *App) close() {
 	a.connMgr.Close(context.Background())
+	a.zones.bottom.UnloadSpreadsheetCache()
 	if a.assets != nil {
 		a.assets.UnloadAssets()
 	}
@@ -178,6 +205,7 @@ func (a *App) update() {
 	a.handleDroppedFiles()
 	display.HandleInput(a.windowMgr.CurrCtx())
 	a.handleQueryResults()
+	a.cursors.spreadsheet.HandleCommitResults()
 }
 
 func (a *App) updateZoneBounds(screenWidth, screenHeight int, commandZoneHeight float32) {
@@ -195,6 +223,33 @@ func (a *App) updateZoneBounds(screenWidth, screenHeight int, commandZoneHeight
 		Height: float32(screenHeight) - (a.splitter.Y + a.splitter.Height/2) - commandZoneHeight,
 	}
 
+	// Tab strip of results takes top of bottom zone
+	if len(a.results.Tabs) > 0 {
+		a.zones.resultTabs.Bounds = a.zones.bottom.Bounds
+		a.zones.resultTabs.Bounds.Height = display.ResultTabsHeight
+		a.zones.bottom.Bounds.Y += display.ResultTabsHeight
+		a.zones.bottom.Bounds.Height -= display.ResultTabsHeight
+	}
+
+	// Notifications panel takes right side of bottom zone while listening
+	if a.connMgr.GetNotificationListener() != nil {
+		const notificationsPanelRatio float32 = 0.4
+		a.zones.notifications.Bounds = a.zones.bottom.Bounds
+		a.zones.notifications.Bounds.Width = a.zones.bottom.Bounds.Width * notificationsPanelRatio
+		a.zones.notifications.Bounds.X = a.zones.bottom.Bounds.Width - a.zones.notifications.Bounds.Width
+		a.zones.bottom.Bounds.Width -= a.zones.notifications.Bounds.Width
+	}
+
+	// Chart takes right side of what is left for spreadsheet
+	if a.chart.IsVisible() {
+		const chartPanelRatio float32 = 0.5
+		a.zones.chart.Bounds = a.zones.bottom.Bounds
+		a.zones.chart.Bounds.Width = a.zones.bottom.Bounds.Width * chartPanelRatio
+		a.zones.chart.Bounds.X = a.zones.bottom.Bounds.X + a.zones.bottom.Bounds.Width - a.zones.chart.Bounds.Width
+		a.zones.bottom.Bounds.Width -= a.zones.chart.Bounds.Width
+	}
+	a.zones.record.Bounds = a.zones.bottom.Bounds
+
 	a.zones.command.Bounds = rl.Rectangle{
 		X:      0,
 		Y:      a.zones.bottom.Bounds.Y + a.zones.bottom.Bounds.Height,
@@ -211,13 +266,26 @@ func (a *App) draw() {
 
 	editorIsFocused := a.cursors.editor.Cursor.IsActive()
 	a.zones.top.DrawEditor(a.assets, a.editGrid, a.cursors.editor.Cursor, editorIsFocused)
-	a.zones.bottom.DrawSpreadsheetZone(a.assets, a.dataGrid, a.cursors.spreadsheet.Cursor)
+	if a.record.IsVisible() {
+		a.zones.record.DrawRecordView(a.assets, a.record, a.search, a.dataGrid, a.cursors.spreadsheet.Cursor)
+	} else {
+		a.zones.bottom.DrawSpreadsheetZone(a.assets, a.dataGrid, a.cursors.spreadsheet.Cursor, a.search)
+	}
+	if len(a.results.Tabs) > 0 {
+		a.zones.resultTabs.DrawResultTabs(a.assets, a.results, &a.cursors.common.Logs)
+	}
+	if listener := a.connMgr.GetNotificationListener(); listener != nil {
+		a.zones.notifications.DrawNotificationsPanel(a.assets, listener)
+	}
+	if a.chart.IsVisible() {
+		a.zones.chart.DrawChart(a.assets, a.chart, a.dataGrid)
+	}
 	if editorIsFocused {
-		a.zones.command.DrawCommandZone(a.cfg, a.assets, a.cursors.editor.Cursor, a.connMgr.GetCurrentConnectionName())
+		a.zones.command.DrawCommandZone(a.cfg, a.assets, a.cursors.editor.Cursor, a.dataGrid, nil, a.connMgr.GetCurrentConnectionName())
 	} else if a.cursors.spreadsheet.Cursor.IsActive() {
-		a.zones.command.DrawCommandZone(a.cfg, a.assets, a.cursors.spreadsheet.Cursor, a.connMgr.GetCurrentConnectionName())
+		a.zones.command.DrawCommandZone(a.cfg, a.assets, a.cursors.spreadsheet.Cursor, a.dataGrid, a.search, a.connMgr.GetCurrentConnectionName())
 	} else if a.cursors.connections.Cursor.IsActive() {
-		a.zones.command.DrawCommandZone(a.cfg, a.assets, a.cursors.connections.Cursor, a.connMgr.GetCurrentConnectionName())
+		a.zones.command.DrawCommandZone(a.cfg, a.assets, a.cursors.connections.Cursor, a.dataGrid, nil, a.connMgr.GetCurrentConnectionName())
 	}
 
 	a.splitter.Draw(a.windowMgr.CurrCtx().Cursor.Type)
@@ -227,6 +295,8 @@ func (a *App) draw() {
 		screenHeight := int32(rl.GetScreenHeight())
 		a.zones.connections.DrawConnectionSelector(a.assets, a.cfg, a.cursors.connections.Cursor, screenWidth, screenHeight, a.connMgr)
 	}
+
+	display.DrawPopup(a.assets, a.popup, int32(rl.GetScreenWidth()), int32(rl.GetScreenHeight()))
 }
 
 func (a *App) handleDroppedFiles() {
@@ -257,7 +327,7 @@ func (a *App) handleDroppedFiles() {
 }
 
 func (a *App) loadSQLFile(path string) {
-	newEg, err := editor.LoadGridFromTextFile(path, a.assets)
+	newEg, err := editor.LoadGridFromTextFile(path, a.assets, a.cfg.Editor.UndoLevels)
 	if err != nil {
 		slog.Error("Failed to load file", slog.String("path", path))
 		a.cursors.editor.Cursor.Common.Logs.Log(fmt.Sprintf("ERR: Failed to parse sql file '%s'", path))
@@ -269,6 +339,7 @@ func (a *App) loadSQLFile(path string) {
 	cur.Reset()
 	cur.Position.MaxCol = a.editGrid.MaxCol
 	cur.Position.MaxColForRows = a.editGrid.Cols
+	cur.Position.Lines = a.editGrid.Text
 	cur.Position.MaxRow = a.editGrid.Rows - 1
 	cur.Common.Logs.Log(fmt.Sprintf("Loaded sql file '%s'", path))
 	slog.Info("Loaded sql file", slog.String("path", path))
@@ -282,12 +353,10 @@ func (a *App) loadCSVFile(path string) {
 		return
 	}
 
-	*a.dataGrid = *newDg
+	a.results.Open(newDg, filepath.Base(path), "")
 	a.dataGrid.UpdateColumnsWidth(a.assets)
 
 	cur := a.cursors.spreadsheet.Cursor
-	cur.Position.MaxCol = a.dataGrid.Cols - 1
-	cur.Position.MaxRow = a.dataGrid.Rows - 1
 	cur.Common.Logs.Log(fmt.Sprintf("Loaded csv file '%s'", path))
 	slog.Info("Loaded csv file", slog.String("path", path))
 }
@@ -314,13 +383,12 @@ func (a *App) handleQueryResults() {
 
 		case done:
 			slog.Debug("Query finished", slog.String("query", connData.QueryText))
-			*a.dataGrid = *newDg
+			a.results.Open(newDg, connData.QueryText, connData.Name)
 			a.dataGrid.UpdateColumnsWidth(a.assets)
-			format.PrintMap(a.dataGrid.Data)
+			// Printing is limited, huge results would stall the UI
+			format.PrintMap(a.dataGrid.Data[:min(len(a.dataGrid.Data), 10)])
 
 			a.cursors.editor.Cursor.Reset()
-			a.cursors.spreadsheet.Cursor.Position.MaxCol = a.dataGrid.Cols
-			a.cursors.spreadsheet.Cursor.Position.MaxRow = a.dataGrid.Rows
 			logs.Log(fmt.Sprintf("'%s' finished after %s and returned %d result(s)", connData.QueryText, runtime, a.dataGrid.Rows))
 
 		default: